@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"math"
+	"time"
+)
+
+// A ClockMapper translates CCFeedbackReport timestamps from the clock of one
+// endpoint to the clock of another, as needed by a relay forwarding feedback
+// between endpoints whose clocks are skewed. The timestamps are the middle 32
+// bits of an NTP timestamp, in units of 1/65536 seconds. The zero value maps
+// every timestamp to itself.
+type ClockMapper struct {
+	// Reference is a source timestamp at which the target clock was Offset
+	// ahead of the source clock
+	Reference uint32
+
+	// Offset is how far the target clock is ahead of the source clock at
+	// Reference. It is negative if the target clock is behind.
+	Offset time.Duration
+
+	// Drift is how much faster the target clock runs than the source clock,
+	// as a fraction: with a Drift of 1e-6, the offset grows by a microsecond
+	// every second after Reference.
+	Drift float64
+}
+
+// Map returns the target timestamp for ts. The time since Reference is
+// computed using serial number arithmetic, so ts may be up to about nine
+// hours before or after Reference, across a wraparound of the timestamp.
+func (m ClockMapper) Map(ts uint32) uint32 {
+	elapsed := float64(int32(ts - m.Reference))
+	offset := m.Offset.Seconds()*reportTimestampsPerSecond + elapsed*m.Drift
+	return ts + uint32(int64(math.Round(offset)))
+}
+
+// MapReport moves r from the source to the target clock by mapping its
+// ReportTimestamp. The arrival time offsets are relative to the report
+// timestamp, so they describe the same arrivals on the target clock and are
+// kept; the drift over their range of about eight seconds is far below
+// their resolution. A relay that has to send the report at a different time
+// on the target clock can then move it there with Rebase.
+func (m ClockMapper) MapReport(r *CCFeedbackReport) {
+	r.ReportTimestamp = m.Map(r.ReportTimestamp)
+}
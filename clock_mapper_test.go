@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockMapper(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Mapper ClockMapper
+		TS     uint32
+		Want   uint32
+	}{
+		{Name: "identity", TS: 0x12345678, Want: 0x12345678},
+		{Name: "fixed offset", Mapper: ClockMapper{Offset: 2 * time.Second}, TS: 1000, Want: 1000 + 2<<16},
+		{Name: "negative offset", Mapper: ClockMapper{Offset: -time.Second / 2}, TS: 1 << 20, Want: 1<<20 - 1<<15},
+		{Name: "offset across wrap", Mapper: ClockMapper{Offset: time.Second}, TS: 0xFFFF8000, Want: 0x00008000},
+		{
+			// 100 seconds after Reference, the target clock is 10ms ahead.
+			Name:   "drift",
+			Mapper: ClockMapper{Reference: 1000, Drift: 1e-4},
+			TS:     1000 + 100<<16,
+			Want:   1000 + 100<<16 + 655,
+		},
+		{
+			Name:   "drift before reference",
+			Mapper: ClockMapper{Reference: 1000 + 100<<16, Offset: time.Second, Drift: 1e-4},
+			TS:     1000,
+			Want:   1000 + 1<<16 - 655,
+		},
+		{
+			Name:   "drift across wrap",
+			Mapper: ClockMapper{Reference: 0xFFFF0000, Drift: -1e-4},
+			TS:     99 << 16,
+			Want:   99<<16 - 655,
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Want, test.Mapper.Map(test.TS))
+		})
+	}
+}
+
+func TestClockMapperMapReport(t *testing.T) {
+	report := CCFeedbackReport{
+		SenderSSRC: 1,
+		ReportBlocks: []CCFeedbackReportBlock{{
+			MediaSSRC: 2,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ArrivalTimeOffset: 1024},
+				{},
+				{Received: true, ArrivalTimeOffset: 0},
+			},
+		}},
+		ReportTimestamp: 10 << 16,
+	}
+	mapper := ClockMapper{Reference: 10 << 16, Offset: 3 * time.Second}
+
+	mapped := report
+	mapper.MapReport(&mapped)
+	assert.Equal(t, uint32(13<<16), mapped.ReportTimestamp)
+	assert.Equal(t, report.ReportBlocks, mapped.ReportBlocks)
+
+	// Moving the mapped report half a second later on the target clock
+	// keeps the arrivals it describes.
+	assert.NoError(t, mapped.Rebase(13<<16+1<<15))
+	assert.Equal(t, []CCFeedbackMetricBlock{
+		{Received: true, ArrivalTimeOffset: 1536},
+		{},
+		{Received: true, ArrivalTimeOffset: 512},
+	}, mapped.ReportBlocks[0].MetricBlocks)
+}
@@ -5,6 +5,7 @@ package rtcp
 
 import (
 	"fmt"
+	"net"
 	"strings"
 )
 
@@ -22,6 +23,67 @@ import (
 // Other RTCP packet types may follow in any order. Packet types may appear more than once.
 type CompoundPacket []Packet
 
+// Build assembles packets into a CompoundPacket in the order required by
+// RFC 3550: SenderReports and ReceiverReports first, followed by the
+// SourceDescriptions, then all other packets, with any Goodbye last. Packets
+// of the same kind keep their relative order. The result is validated, so an
+// error is returned if no report or no CNAME is present.
+func Build(packets ...Packet) (CompoundPacket, error) {
+	var senderReports, receiverReports, descriptions, others, goodbyes []Packet
+	for _, pkt := range packets {
+		switch pkt.(type) {
+		case *SenderReport:
+			senderReports = append(senderReports, pkt)
+		case *ReceiverReport:
+			receiverReports = append(receiverReports, pkt)
+		case *SourceDescription:
+			descriptions = append(descriptions, pkt)
+		case *Goodbye:
+			goodbyes = append(goodbyes, pkt)
+		default:
+			others = append(others, pkt)
+		}
+	}
+
+	c := make(CompoundPacket, 0, len(packets))
+	c = append(c, senderReports...)
+	c = append(c, receiverReports...)
+	c = append(c, descriptions...)
+	c = append(c, others...)
+	c = append(c, goodbyes...)
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NewFeedbackCompound assembles the SenderReport and congestion control
+// feedback that SCReAM-style senders emit together. The SenderReport leads,
+// followed by a SourceDescription carrying cname for the sender SSRC, as every
+// compound packet needs one, and the feedback report. Both reports must share
+// the sender SSRC. The feedback report is checked with ValidateRFC8888. The
+// result passes Validate and encodes into a single buffer with Marshal.
+func NewFeedbackCompound(sr *SenderReport, cname string, fb *CCFeedbackReport) (CompoundPacket, error) {
+	if sr == nil {
+		return nil, errBadFirstPacket
+	}
+	if cname == "" {
+		return nil, errMissingCNAME
+	}
+	if fb == nil {
+		return nil, errMissingFeedback
+	}
+	if fb.SenderSSRC != sr.SSRC {
+		return nil, errSenderSSRCMismatch
+	}
+	if err := fb.ValidateRFC8888(); err != nil {
+		return nil, err
+	}
+
+	return CompoundPacket{sr, NewCNAMESourceDescription(sr.SSRC, cname), fb}, nil
+}
+
 // Validate returns an error if this is not an RFC-compliant CompoundPacket.
 func (c CompoundPacket) Validate() error {
 	if len(c) == 0 {
@@ -72,6 +134,67 @@ func (c CompoundPacket) Validate() error {
 	return errMissingCNAME
 }
 
+// AddPadding pads the CompoundPacket so that its marshaled size is a multiple
+// of alignTo, as needed to reach a cipher block boundary for SRTCP. The
+// padding is appended to the last packet, which gets its padding bit set and
+// its length updated to cover the padding octets, the last of which holds
+// the padding count (RFC 3550 Section 6.4.1).
+//
+// Since individual packet types have no way to carry padding themselves, the
+// last packet is replaced by a RawPacket holding its padded encoding. For
+// that reason it must not be a SenderReport, ReceiverReport or
+// SourceDescription, whose types Validate relies on. alignTo must be a
+// positive multiple of 4.
+func AddPadding(packets CompoundPacket, alignTo int) error {
+	if alignTo <= 0 || alignTo%4 != 0 {
+		return errWrongPadding
+	}
+	if len(packets) == 0 {
+		return errEmptyCompound
+	}
+
+	last := len(packets) - 1
+	switch packets[last].(type) {
+	case *SenderReport, *ReceiverReport, *SourceDescription:
+		return errUnpaddablePacket
+	}
+
+	paddingLength := alignTo - packets.MarshalSize()%alignTo
+	if paddingLength == alignTo {
+		return nil
+	}
+	if paddingLength > 0xFF {
+		return errWrongPadding
+	}
+
+	data, err := packets[last].Marshal()
+	if err != nil {
+		return err
+	}
+
+	var h Header
+	if err := h.Unmarshal(data); err != nil {
+		return err
+	}
+	if h.Padding {
+		return errWrongPadding
+	}
+	h.Padding = true
+	h.Length += uint16(paddingLength / 4)
+
+	raw := make(RawPacket, len(data)+paddingLength)
+	copy(raw, data)
+	headerData, err := h.Marshal()
+	if err != nil {
+		return err
+	}
+	copy(raw, headerData)
+	raw[len(raw)-1] = byte(paddingLength)
+	packets[last] = &raw
+
+	return nil
+}
+
 // CNAME returns the CNAME that *must* be present in every CompoundPacket
 func (c CompoundPacket) CNAME() (string, error) {
 	var err error
@@ -100,6 +223,30 @@ func (c CompoundPacket) CNAME() (string, error) {
 	return "", errMissingCNAME
 }
 
+// CNAMEForSSRC returns the CNAME that a SourceDescription among packets,
+// such as a decoded compound packet, gives for ssrc, so that the feedback of
+// a CCFeedbackReport can be correlated with the media source it describes.
+// The boolean is false if no chunk for ssrc carries a CNAME.
+func CNAMEForSSRC(packets []Packet, ssrc uint32) (string, bool) {
+	for _, pkt := range packets {
+		sdes, ok := pkt.(*SourceDescription)
+		if !ok {
+			continue
+		}
+		for _, c := range sdes.Chunks {
+			if c.Source != ssrc {
+				continue
+			}
+			for _, it := range c.Items {
+				if it.Type == SDESCNAME {
+					return it.Text, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
 // Marshal encodes the CompoundPacket as binary.
 func (c CompoundPacket) Marshal() ([]byte, error) {
 	if err := c.Validate(); err != nil {
@@ -110,6 +257,41 @@ func (c CompoundPacket) Marshal() ([]byte, error) {
 	return Marshal(p)
 }
 
+// AppendBuffers encodes the CompoundPacket like Marshal, but appends the
+// encoding of every packet to bufs as a separate buffer instead of
+// concatenating them, for scatter-gather writes. If the last packet is
+// padded, as by AddPadding, its padding octets are appended as a buffer of
+// their own.
+func (c CompoundPacket) AppendBuffers(bufs net.Buffers) (net.Buffers, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	for i, p := range c {
+		data, err := p.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		if i == len(c)-1 {
+			var h Header
+			if err := h.Unmarshal(data); err != nil {
+				return nil, err
+			}
+			if h.Padding {
+				paddingLength := int(data[len(data)-1])
+				if paddingLength == 0 || paddingLength > len(data)-headerLength {
+					return nil, errWrongPadding
+				}
+				split := len(data) - paddingLength
+				bufs = append(bufs, data[:split:split], data[split:])
+				continue
+			}
+		}
+		bufs = append(bufs, data)
+	}
+	return bufs, nil
+}
+
 // MarshalSize returns the size of the packet once marshaled
 func (c CompoundPacket) MarshalSize() int {
 	l := 0
@@ -146,6 +328,11 @@ func (c CompoundPacket) DestinationSSRC() []uint32 {
 	return c[0].DestinationSSRC()
 }
 
+// Kind returns KindCompound.
+func (c CompoundPacket) Kind() PacketKind {
+	return KindCompound
+}
+
 func (c CompoundPacket) String() string {
 	out := "CompoundPacket\n"
 	for _, p := range c {
@@ -4,7 +4,10 @@
 package rtcp
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"net"
 	"reflect"
 	"testing"
 
@@ -276,3 +279,294 @@ func TestCompoundPacketRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestBuild(t *testing.T) {
+	cname := NewCNAMESourceDescription(1234, "cname")
+	sr := &SenderReport{SSRC: 1234}
+	rr := &ReceiverReport{SSRC: 1234}
+	pli := &PictureLossIndication{SenderSSRC: 1234, MediaSSRC: 4321}
+	ccfb := &CCFeedbackReport{SenderSSRC: 1234}
+	bye := &Goodbye{Sources: []uint32{1234}}
+
+	for _, test := range []struct {
+		Name    string
+		Packets []Packet
+		Want    CompoundPacket
+		Err     error
+	}{
+		{
+			Name:    "already ordered",
+			Packets: []Packet{sr, cname, pli},
+			Want:    CompoundPacket{sr, cname, pli},
+		},
+		{
+			Name:    "misplaced sdes",
+			Packets: []Packet{ccfb, sr, pli, cname},
+			Want:    CompoundPacket{sr, cname, ccfb, pli},
+		},
+		{
+			Name:    "reports first",
+			Packets: []Packet{bye, rr, cname, sr},
+			Want:    CompoundPacket{sr, rr, cname, bye},
+		},
+		{
+			Name:    "missing leading report",
+			Packets: []Packet{cname, ccfb},
+			Err:     errBadFirstPacket,
+		},
+		{
+			Name:    "missing cname",
+			Packets: []Packet{sr, ccfb},
+			Err:     errPacketBeforeCNAME,
+		},
+		{
+			Name: "empty",
+			Err:  errEmptyCompound,
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			c, err := Build(test.Packets...)
+			assert.ErrorIs(t, err, test.Err)
+			assert.Equal(t, test.Want, c)
+		})
+	}
+}
+
+func TestAddPadding(t *testing.T) {
+	newCompound := func() CompoundPacket {
+		return CompoundPacket{
+			&ReceiverReport{SSRC: 1234},
+			NewCNAMESourceDescription(1234, "cname"),
+			NewCCFeedbackReport(1234, 0x01020304, []CCFeedbackReportBlock{{
+				MediaSSRC:     4321,
+				BeginSequence: 1,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 10},
+					{Received: false},
+					{Received: true, ECN: ECNCE, ArrivalTimeOffset: 20},
+					{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 30},
+				},
+			}}),
+		}
+	}
+
+	// The compound packet is 52 bytes: already aligned to 4, but 12 bytes
+	// short of a 16 byte boundary.
+	for _, test := range []struct {
+		AlignTo       int
+		PaddingLength int
+	}{
+		{AlignTo: 4, PaddingLength: 0},
+		{AlignTo: 16, PaddingLength: 12},
+	} {
+		test := test
+		t.Run(fmt.Sprintf("align to %d", test.AlignTo), func(t *testing.T) {
+			c := newCompound()
+			unpadded, err := c.Marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, 52, len(unpadded))
+
+			assert.NoError(t, AddPadding(c, test.AlignTo))
+			data, err := c.Marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, len(unpadded)+test.PaddingLength, len(data))
+			assert.Zero(t, len(data)%test.AlignTo)
+			if test.PaddingLength > 0 {
+				assert.Equal(t, byte(test.PaddingLength), data[len(data)-1])
+			}
+
+			var decoded CompoundPacket
+			assert.NoError(t, decoded.Unmarshal(data))
+			assert.Equal(t, newCompound()[2], decoded[2])
+		})
+	}
+
+	t.Run("pads when unaligned", func(t *testing.T) {
+		c := newCompound()
+		assert.NoError(t, AddPadding(c, 16))
+		last, ok := c[2].(*RawPacket)
+		assert.True(t, ok)
+		h := last.Header()
+		assert.True(t, h.Padding)
+		assert.Equal(t, len(*last), 4*(int(h.Length)+1))
+	})
+
+	for name, test := range map[string]struct {
+		Packets CompoundPacket
+		AlignTo int
+		Err     error
+	}{
+		"empty":             {Packets: CompoundPacket{}, AlignTo: 4, Err: errEmptyCompound},
+		"unaligned":         {Packets: newCompound(), AlignTo: 6, Err: errWrongPadding},
+		"zero":              {Packets: newCompound(), AlignTo: 0, Err: errWrongPadding},
+		"padding too large": {Packets: newCompound(), AlignTo: 1024, Err: errWrongPadding},
+		"last is sdes":      {Packets: newCompound()[:2], AlignTo: 16, Err: errUnpaddablePacket},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, AddPadding(test.Packets, test.AlignTo), test.Err)
+		})
+	}
+}
+
+func TestCompoundPacketAppendBuffers(t *testing.T) {
+	newCompound := func() CompoundPacket {
+		return CompoundPacket{
+			&ReceiverReport{SSRC: 1234},
+			NewCNAMESourceDescription(1234, "cname"),
+			&Goodbye{Sources: []uint32{1234}},
+		}
+	}
+
+	t.Run("unpadded", func(t *testing.T) {
+		c := newCompound()
+		want, err := c.Marshal()
+		assert.NoError(t, err)
+
+		prefix := []byte{0xFF}
+		bufs, err := c.AppendBuffers(net.Buffers{prefix})
+		assert.NoError(t, err)
+		if assert.Len(t, bufs, 4) {
+			assert.Equal(t, prefix, bufs[0])
+		}
+		var buf bytes.Buffer
+		rest := bufs[1:]
+		_, err = rest.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, want, buf.Bytes())
+	})
+
+	t.Run("padded", func(t *testing.T) {
+		c := newCompound()
+		assert.NoError(t, AddPadding(c, 16))
+		want, err := c.Marshal()
+		assert.NoError(t, err)
+
+		bufs, err := c.AppendBuffers(nil)
+		assert.NoError(t, err)
+		if assert.Len(t, bufs, 4) {
+			// The padding octets are a buffer of their own.
+			padding := bufs[3]
+			assert.Equal(t, 12, len(padding))
+			assert.Equal(t, byte(len(padding)), padding[len(padding)-1])
+		}
+		assert.Equal(t, want, bytes.Join(bufs, nil))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := newCompound()[:1].AppendBuffers(nil)
+		assert.ErrorIs(t, err, errMissingCNAME)
+	})
+}
+
+func TestNewFeedbackCompound(t *testing.T) {
+	sr := &SenderReport{
+		SSRC:        0x902f9e2e,
+		NTPTime:     0xda8bd1fcdddda05a,
+		RTPTime:     0xaaf4edd5,
+		PacketCount: 1,
+		OctetCount:  2,
+	}
+	fb := &CCFeedbackReport{
+		SenderSSRC:      0x902f9e2e,
+		ReportTimestamp: 0x12345678,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{
+				MediaSSRC:     0xbc5e9a40,
+				BeginSequence: 100,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 10},
+					{Received: false},
+				},
+			},
+		},
+	}
+
+	c, err := NewFeedbackCompound(sr, "sender", fb)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Validate())
+	data, err := c.Marshal()
+	assert.NoError(t, err)
+
+	var decoded CompoundPacket
+	assert.NoError(t, decoded.Unmarshal(data))
+	assert.Equal(t, CompoundPacket{sr, NewCNAMESourceDescription(sr.SSRC, "sender"), fb}, decoded)
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewFeedbackCompound(nil, "sender", fb)
+		assert.ErrorIs(t, err, errBadFirstPacket)
+
+		_, err = NewFeedbackCompound(sr, "", fb)
+		assert.ErrorIs(t, err, errMissingCNAME)
+
+		_, err = NewFeedbackCompound(sr, "sender", nil)
+		assert.ErrorIs(t, err, errMissingFeedback)
+
+		_, err = NewFeedbackCompound(&SenderReport{SSRC: 1}, "sender", fb)
+		assert.ErrorIs(t, err, errSenderSSRCMismatch)
+
+		invalid := &CCFeedbackReport{
+			SenderSSRC: sr.SSRC,
+			ReportBlocks: []CCFeedbackReportBlock{
+				{MetricBlocks: []CCFeedbackMetricBlock{{ArrivalTimeOffset: 0x2000}, {}}},
+			},
+		}
+		_, err = NewFeedbackCompound(sr, "sender", invalid)
+		assert.ErrorIs(t, err, errArrivalTimeOffset)
+	})
+}
+
+func TestCNAMEForSSRC(t *testing.T) {
+	const (
+		senderSSRC = 0x902f9e2e
+		mediaSSRC  = 0xbc5e9a40
+	)
+	fb := &CCFeedbackReport{
+		SenderSSRC:      senderSSRC,
+		ReportTimestamp: 0x12345678,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{
+				MediaSSRC:     mediaSSRC,
+				BeginSequence: 100,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 10},
+					{Received: false},
+				},
+			},
+		},
+	}
+	compound := CompoundPacket{
+		&ReceiverReport{SSRC: senderSSRC},
+		&SourceDescription{Chunks: []SourceDescriptionChunk{
+			{Source: senderSSRC, Items: []SourceDescriptionItem{{Type: SDESCNAME, Text: "receiver"}}},
+			{Source: mediaSSRC, Items: []SourceDescriptionItem{
+				{Type: SDESName, Text: "camera"},
+				{Type: SDESCNAME, Text: "sender"},
+			}},
+			{Source: 1, Items: []SourceDescriptionItem{{Type: SDESName, Text: "no CNAME"}}},
+		}},
+		fb,
+	}
+	data, err := compound.Marshal()
+	assert.NoError(t, err)
+	packets, err := Unmarshal(data)
+	assert.NoError(t, err)
+
+	for _, ssrc := range fb.DestinationSSRC() {
+		cname, ok := CNAMEForSSRC(packets, ssrc)
+		assert.True(t, ok)
+		assert.Equal(t, "sender", cname)
+	}
+
+	cname, ok := CNAMEForSSRC(packets, senderSSRC)
+	assert.True(t, ok)
+	assert.Equal(t, "receiver", cname)
+
+	for _, ssrc := range []uint32{1, 2} {
+		_, ok := CNAMEForSSRC(packets, ssrc)
+		assert.False(t, ok, "SSRC %d", ssrc)
+	}
+	_, ok = CNAMEForSSRC(nil, mediaSSRC)
+	assert.False(t, ok)
+}
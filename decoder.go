@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Framing describes how RTCP datagrams are delimited on the stream a Decoder
+// reads from.
+type Framing int
+
+const (
+	// FramingDatagram treats the data returned by each Read as one datagram,
+	// as returned by a packet oriented connection such as UDP.
+	FramingDatagram Framing = iota
+
+	// FramingLengthPrefixed expects every datagram to be preceded by its
+	// length as a 16-bit big-endian integer, as used when RTP and RTCP are
+	// carried over connection-oriented transports (RFC 4571).
+	FramingLengthPrefixed
+)
+
+// maxDatagramLength is the largest datagram either framing can carry.
+const maxDatagramLength = 0xFFFF
+
+// A Decoder reads RTCP packets from an io.Reader.
+type Decoder struct {
+	r       io.Reader
+	framing Framing
+	buf     []byte
+	pending []Packet
+}
+
+// NewDecoder returns a Decoder reading datagrams framed by framing from r.
+func NewDecoder(r io.Reader, framing Framing) *Decoder {
+	return &Decoder{r: r, framing: framing}
+}
+
+// ReadPacket returns the next packet from the stream. A datagram holding a
+// compound packet is decoded at once and its packets are returned by
+// successive calls. io.EOF is returned once the stream ends at a datagram
+// boundary, io.ErrUnexpectedEOF if it ends within a length-prefixed datagram.
+func (d *Decoder) ReadPacket() (Packet, error) {
+	for len(d.pending) == 0 {
+		datagram, err := d.readDatagram()
+		if err != nil {
+			return nil, err
+		}
+
+		packets, err := Unmarshal(datagram)
+		if err != nil {
+			return nil, err
+		}
+		d.pending = packets
+	}
+
+	p := d.pending[0]
+	d.pending = d.pending[1:]
+	return p, nil
+}
+
+func (d *Decoder) readDatagram() ([]byte, error) {
+	if d.buf == nil {
+		d.buf = make([]byte, maxDatagramLength)
+	}
+
+	var n int
+	switch d.framing {
+	case FramingLengthPrefixed:
+		if _, err := io.ReadFull(d.r, d.buf[:2]); err != nil {
+			return nil, err
+		}
+		n = int(binary.BigEndian.Uint16(d.buf[:2]))
+		if _, err := io.ReadFull(d.r, d.buf[:n]); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	default:
+		for n == 0 {
+			var err error
+			if n, err = d.r.Read(d.buf); n == 0 && err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Decoded packets may reference the datagram, so it must not share
+	// the read buffer.
+	datagram := make([]byte, n)
+	copy(datagram, d.buf[:n])
+	return datagram, nil
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// datagramReader returns one datagram per Read, like a UDP connection.
+type datagramReader [][]byte
+
+func (r *datagramReader) Read(b []byte) (int, error) {
+	if len(*r) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, (*r)[0])
+	*r = (*r)[1:]
+	return n, nil
+}
+
+func TestDecoder(t *testing.T) {
+	first := NewCCFeedbackReport(1, 0x01020304, []CCFeedbackReportBlock{{
+		MediaSSRC:     2,
+		BeginSequence: 10,
+		MetricBlocks: []CCFeedbackMetricBlock{
+			{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 3},
+			{Received: false},
+		},
+	}})
+	second := &ReceiverReport{SSRC: 3, ProfileExtensions: []byte{}}
+
+	firstData, err := first.Marshal()
+	assert.NoError(t, err)
+	secondData, err := second.Marshal()
+	assert.NoError(t, err)
+
+	lengthPrefixed := func(datagrams ...[]byte) []byte {
+		var out []byte
+		for _, datagram := range datagrams {
+			out = binary.BigEndian.AppendUint16(out, uint16(len(datagram)))
+			out = append(out, datagram...)
+		}
+		return out
+	}
+
+	for _, test := range []struct {
+		Name    string
+		Reader  io.Reader
+		Framing Framing
+	}{
+		{
+			Name:    "length prefixed",
+			Reader:  bytes.NewReader(lengthPrefixed(firstData, secondData)),
+			Framing: FramingLengthPrefixed,
+		},
+		{
+			Name:    "length prefixed compound",
+			Reader:  bytes.NewReader(lengthPrefixed(append(append([]byte{}, firstData...), secondData...))),
+			Framing: FramingLengthPrefixed,
+		},
+		{
+			Name:    "datagrams",
+			Reader:  &datagramReader{firstData, secondData},
+			Framing: FramingDatagram,
+		},
+		{
+			Name:    "compound datagram",
+			Reader:  bytes.NewReader(append(append([]byte{}, firstData...), secondData...)),
+			Framing: FramingDatagram,
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			d := NewDecoder(test.Reader, test.Framing)
+
+			p, err := d.ReadPacket()
+			assert.NoError(t, err)
+			assert.Equal(t, first, p)
+
+			p, err = d.ReadPacket()
+			assert.NoError(t, err)
+			assert.Equal(t, second, p)
+
+			_, err = d.ReadPacket()
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+
+	t.Run("truncated length prefixed", func(t *testing.T) {
+		data := lengthPrefixed(firstData)
+		d := NewDecoder(bytes.NewReader(data[:len(data)-1]), FramingLengthPrefixed)
+		_, err := d.ReadPacket()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("invalid datagram", func(t *testing.T) {
+		d := NewDecoder(bytes.NewReader(firstData[:len(firstData)-4]), FramingDatagram)
+		_, err := d.ReadPacket()
+		assert.Error(t, err)
+	})
+}
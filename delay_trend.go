@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import "time"
+
+// A DelayTrendEstimator tracks the trend of the one-way delay of packets
+// reported on by successive CCFeedbackReports, as used by delay-based
+// congestion control such as GCC. Arrival times are reconstructed on the
+// receiver clock from the report timestamp and the arrival time offsets, and
+// are compared to the send times known to the sender. The clocks need not be
+// synchronized: their offset is constant and does not affect the trend.
+//
+// Every sequence number is taken once, the first time a report carries its
+// arrival time; sequence numbers up to the highest one taken so far for a
+// media source are skipped, so overlapping reports and packets reported
+// late do not count twice.
+type DelayTrendEstimator struct {
+	window int
+	sent   func(ssrc uint32, seq uint16) (time.Time, bool)
+
+	highest map[uint32]uint16
+
+	haveTimestamp bool
+	lastTimestamp uint32
+	// reportTime is the unwrapped report timestamp in 1/65536 seconds
+	reportTime int64
+
+	base    time.Time
+	samples []delaySample
+}
+
+type delaySample struct {
+	// sent is the send time in seconds since base
+	sent float64
+	// delay is the one-way delay in seconds, including the clock offset
+	delay float64
+}
+
+// NewDelayTrendEstimator returns an estimator computing the trend over the
+// last window packets, at least 2. sent returns the time the packet with
+// sequence number seq of ssrc was sent, or false if it is not known, in
+// which case the packet is ignored.
+func NewDelayTrendEstimator(window int, sent func(ssrc uint32, seq uint16) (time.Time, bool)) *DelayTrendEstimator {
+	if window < 2 {
+		window = 2
+	}
+	return &DelayTrendEstimator{
+		window:  window,
+		sent:    sent,
+		highest: make(map[uint32]uint16),
+	}
+}
+
+// Ingest adds the arrivals of the packets of ssrc reported on by r. Reports
+// must be ingested in the order they were sent, so that the report timestamp
+// can be followed across its wraparound.
+func (e *DelayTrendEstimator) Ingest(r *CCFeedbackReport, ssrc uint32) {
+	if e.haveTimestamp {
+		e.reportTime += int64(int32(r.ReportTimestamp - e.lastTimestamp))
+	} else {
+		e.reportTime = int64(r.ReportTimestamp)
+		e.haveTimestamp = true
+	}
+	e.lastTimestamp = r.ReportTimestamp
+	reportTime := float64(e.reportTime) / reportTimestampsPerSecond
+
+	for _, block := range r.ReportBlocks {
+		if block.MediaSSRC != ssrc {
+			continue
+		}
+		for i, mb := range block.MetricBlocks {
+			if !mb.HasArrivalTime() {
+				continue
+			}
+			seq := block.BeginSequence + uint16(i)
+			if highest, ok := e.highest[ssrc]; ok {
+				if distance := seq - highest; distance == 0 || distance >= 1<<15 {
+					continue
+				}
+			}
+			sent, ok := e.sent(ssrc, seq)
+			if !ok {
+				continue
+			}
+			e.highest[ssrc] = seq
+
+			if e.base.IsZero() {
+				e.base = sent
+			}
+			arrival := reportTime - float64(mb.ArrivalTimeOffset)/arrivalTimeOffsetsPerSecond
+			sample := delaySample{sent: sent.Sub(e.base).Seconds()}
+			sample.delay = arrival - sample.sent
+			e.add(sample)
+		}
+	}
+}
+
+func (e *DelayTrendEstimator) add(sample delaySample) {
+	if len(e.samples) == e.window {
+		copy(e.samples, e.samples[1:])
+		e.samples = e.samples[:len(e.samples)-1]
+	}
+	e.samples = append(e.samples, sample)
+}
+
+// Slope returns the least squares slope of the one-way delay over the send
+// time of the packets in the window, in seconds of delay per second. It is
+// positive while queues build up, about 0 while the delay is stable and
+// negative while queues drain. Slope returns 0 until two packets with
+// different send times have been ingested.
+func (e *DelayTrendEstimator) Slope() float64 {
+	n := float64(len(e.samples))
+	var sumX, sumY float64
+	for _, s := range e.samples {
+		sumX += s.sent
+		sumY += s.delay
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var covariance, variance float64
+	for _, s := range e.samples {
+		dx := s.sent - meanX
+		covariance += dx * (s.delay - meanY)
+		variance += dx * dx
+	}
+	if variance == 0 {
+		return 0
+	}
+	return covariance / variance
+}
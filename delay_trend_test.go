@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelayTrendEstimator(t *testing.T) {
+	// Packets are sent every 32/1024 seconds, which times, arrival time
+	// offsets and report timestamps all represent exactly.
+	const interval = 32 * time.Second / 1024
+	start := time.Unix(1000, 0)
+	sent := func(ssrc uint32, seq uint16) (time.Time, bool) {
+		if ssrc != 1 || seq >= 1000 {
+			return time.Time{}, false
+		}
+		return start.Add(time.Duration(seq) * interval), true
+	}
+
+	// report returns a report on seqs begin to end from a receiver whose
+	// clock reads timestamp when packet 0 is sent, delaying packet seq by
+	// delay(seq) 1/1024 seconds.
+	report := func(timestamp uint32, begin, end uint16, delay func(seq uint16) int) *CCFeedbackReport {
+		// Report when the last packet arrived.
+		reportTime := int(end-1)*32 + delay(end-1)
+		block := CCFeedbackReportBlock{MediaSSRC: 1, BeginSequence: begin}
+		for seq := begin; seq != end; seq++ {
+			arrival := int(seq)*32 + delay(seq)
+			block.MetricBlocks = append(block.MetricBlocks, CCFeedbackMetricBlock{
+				Received:          true,
+				ArrivalTimeOffset: uint16(reportTime - arrival),
+			})
+		}
+		return &CCFeedbackReport{
+			ReportBlocks:    []CCFeedbackReportBlock{block},
+			ReportTimestamp: timestamp + uint32(reportTime*64),
+		}
+	}
+
+	t.Run("increasing", func(t *testing.T) {
+		// The delay grows by 2/1024 seconds for every 32/1024 seconds.
+		delay := func(seq uint16) int { return 100 + 2*int(seq) }
+		e := NewDelayTrendEstimator(16, sent)
+		assert.Zero(t, e.Slope())
+		// The report timestamp wraps between the two overlapping reports.
+		e.Ingest(report(0xFFFF7530, 0, 10, delay), 1)
+		assert.InDelta(t, 2.0/32, e.Slope(), 1e-9)
+		e.Ingest(report(0xFFFF7530, 5, 20, delay), 1)
+		assert.InDelta(t, 2.0/32, e.Slope(), 1e-9)
+		assert.Len(t, e.samples, 16)
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		delay := func(uint16) int { return 100 }
+		e := NewDelayTrendEstimator(16, sent)
+		e.Ingest(report(1<<20, 0, 8, delay), 1)
+		e.Ingest(report(1<<20, 8, 16, delay), 1)
+		assert.InDelta(t, 0, e.Slope(), 1e-9)
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		delay := func(seq uint16) int { return 1000 - 4*int(seq) }
+		e := NewDelayTrendEstimator(100, sent)
+		e.Ingest(report(0, 0, 50, delay), 1)
+		assert.InDelta(t, -4.0/32, e.Slope(), 1e-9)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		// The delay grows, then stays stable long enough to fill the window.
+		delay := func(seq uint16) int {
+			if seq < 20 {
+				return 100 + 10*int(seq)
+			}
+			return 300
+		}
+		e := NewDelayTrendEstimator(10, sent)
+		e.Ingest(report(0, 0, 20, delay), 1)
+		assert.Greater(t, e.Slope(), 0.0)
+		e.Ingest(report(0, 20, 30, delay), 1)
+		assert.InDelta(t, 0, e.Slope(), 1e-9)
+	})
+
+	t.Run("ignored packets", func(t *testing.T) {
+		delay := func(uint16) int { return 100 }
+		r := report(0, 0, 4, delay)
+		r.ReportBlocks[0].MetricBlocks[1] = CCFeedbackMetricBlock{}
+		r.ReportBlocks[0].MetricBlocks[2].ArrivalTimeOffset = 0x1FFF
+		r.ReportBlocks = append(r.ReportBlocks, CCFeedbackReportBlock{
+			MediaSSRC:    2,
+			MetricBlocks: []CCFeedbackMetricBlock{{Received: true}, {Received: true}},
+		})
+
+		e := NewDelayTrendEstimator(16, sent)
+		e.Ingest(r, 1)
+		assert.Len(t, e.samples, 2)
+		// Send times of 2 are unknown.
+		e.Ingest(r, 2)
+		assert.Len(t, e.samples, 2)
+	})
+}
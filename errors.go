@@ -5,6 +5,10 @@ package rtcp
 
 import "errors"
 
+// ErrUnsupportedPacket is wrapped by every UnsupportedPacketError, so callers
+// can test for an unsupported packet type with errors.Is.
+var ErrUnsupportedPacket = errors.New("rtcp: unsupported packet type")
+
 var (
 	errWrongMarshalSize         = errors.New("rtcp: wrong marshal size")
 	errInvalidTotalLost         = errors.New("rtcp: invalid total lost count")
@@ -13,17 +17,22 @@ var (
 	errBadFirstPacket           = errors.New("rtcp: first packet in compound must be SR or RR")
 	errMissingCNAME             = errors.New("rtcp: compound missing SourceDescription with CNAME")
 	errPacketBeforeCNAME        = errors.New("rtcp: feedback packet seen before CNAME")
+	errMissingFeedback          = errors.New("rtcp: compound missing feedback report")
+	errSenderSSRCMismatch       = errors.New("rtcp: feedback sender SSRC does not match sender report")
 	errTooManyReports           = errors.New("rtcp: too many reports")
 	errTooManyChunks            = errors.New("rtcp: too many chunks")
 	errTooManySources           = errors.New("rtcp: too many sources")
 	errPacketTooShort           = errors.New("rtcp: packet too short")
+	errPacketTooLarge           = errors.New("rtcp: packet exceeds the maximum length")
 	errWrongType                = errors.New("rtcp: wrong packet type")
 	errSDESTextTooLong          = errors.New("rtcp: sdes must be < 255 octets long")
 	errSDESMissingType          = errors.New("rtcp: sdes item missing type")
 	errReasonTooLong            = errors.New("rtcp: reason must be < 255 octets long")
 	errBadVersion               = errors.New("rtcp: invalid packet version")
 	errBadLength                = errors.New("rtcp: invalid packet length")
+	errLengthMismatch           = errors.New("rtcp: header length does not match packet size")
 	errWrongPadding             = errors.New("rtcp: invalid padding value")
+	errUnpaddablePacket         = errors.New("rtcp: packet type cannot carry padding")
 	errWrongFeedbackType        = errors.New("rtcp: wrong feedback message type")
 	errWrongPayloadType         = errors.New("rtcp: wrong payload type")
 	errHeaderTooSmall           = errors.New("rtcp: header length is too small")
@@ -31,6 +40,7 @@ var (
 	errMissingREMBidentifier    = errors.New("missing REMB identifier")
 	errSSRCNumAndLengthMismatch = errors.New("SSRC num and length do not match")
 	errInvalidSizeOrStartIndex  = errors.New("invalid size or startIndex")
+	errValueTooLarge            = errors.New("value does not fit into size bits")
 	errInvalidBitrate           = errors.New("invalid bitrate")
 	errWrongChunkType           = errors.New("rtcp: wrong chunk type")
 	errBadStructMemberType      = errors.New("rtcp: struct contains unexpected member type")
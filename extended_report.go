@@ -13,6 +13,9 @@ import (
 // more report blocks, each of which conveys a different kind of
 // information.
 //
+// The reserved bits of the header and the reserved fields of the standard
+// report blocks are ignored when decoding and written as 0 when encoding.
+//
 //	0                   1                   2                   3
 //	0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
 //
@@ -550,6 +553,14 @@ func (x ExtendedReport) MarshalSize() int {
 	return wireSize(x)
 }
 
+// Header returns the Header associated with this packet.
+func (x *ExtendedReport) Header() Header {
+	return Header{
+		Type:   TypeExtendedReport,
+		Length: uint16(wireSize(*x) / 4),
+	}
+}
+
 // Marshal encodes the ExtendedReport in binary
 func (x ExtendedReport) Marshal() ([]byte, error) {
 	for _, p := range x.Reports {
@@ -654,6 +665,11 @@ func (x *ExtendedReport) DestinationSSRC() []uint32 {
 	return ssrc
 }
 
+// Kind returns KindExtendedReport.
+func (x *ExtendedReport) Kind() PacketKind {
+	return KindExtendedReport
+}
+
 func (x *ExtendedReport) String() string {
 	return stringify(x)
 }
@@ -4,6 +4,7 @@
 package rtcp
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"testing"
@@ -239,6 +240,25 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestReservedHeaderBitsZeroed(t *testing.T) {
+	encoded := encodedPacket()
+	withReserved := append([]byte{}, encoded...)
+	// Set all five reserved bits of the header.
+	withReserved[0] |= countMask
+
+	p := new(ExtendedReport)
+	if err := p.Unmarshal(withReserved); err != nil {
+		t.Fatalf("Error unmarshaling packet: %v", err)
+	}
+	rawPacket, err := p.Marshal()
+	if err != nil {
+		t.Fatalf("Error marshaling packet: %v", err)
+	}
+	if !bytes.Equal(rawPacket, encoded) {
+		t.Errorf("Re-encoded packet does not have the reserved bits zeroed: got %x, want %x", rawPacket, encoded)
+	}
+}
+
 func TestDecode(t *testing.T) {
 	encoded := encodedPacket()
 	expected := testPacket()
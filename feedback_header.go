@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import "encoding/binary"
+
+// feedbackHeaderLength is the size of a FeedbackHeader once marshaled
+const feedbackHeaderLength = 2 * ssrcLength
+
+// A FeedbackHeader holds the two SSRCs that follow the RTCP header of the
+// feedback messages defined by RFC 4585, Section 6.1, ahead of their Feedback
+// Control Information: the SSRC of the packet sender and of the media source
+// the feedback is about. PictureLossIndication, SliceLossIndication,
+// FullIntraRequest, TransportLayerNack and RapidResynchronizationRequest
+// share this layout and return it from their FeedbackHeader method.
+//
+// The packet types keep their own SenderSSRC and MediaSSRC fields rather
+// than embedding a FeedbackHeader, so that existing composite literals keep
+// compiling. CCFeedbackReport only shares the SenderSSRC: RFC 8888 reports on
+// several media sources, so the MediaSSRC lives in each report block.
+type FeedbackHeader struct {
+	// SSRC of the sender of the feedback
+	SenderSSRC uint32
+
+	// SSRC of the media source the feedback is about
+	MediaSSRC uint32
+}
+
+// Marshal encodes the FeedbackHeader in binary
+func (f FeedbackHeader) Marshal() ([]byte, error) {
+	buf := make([]byte, feedbackHeaderLength)
+	if _, err := f.MarshalTo(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// MarshalTo encodes the FeedbackHeader into buf and returns the number of
+// bytes written
+func (f FeedbackHeader) MarshalTo(buf []byte) (int, error) {
+	if len(buf) < feedbackHeaderLength {
+		return 0, errPacketTooShort
+	}
+	binary.BigEndian.PutUint32(buf, f.SenderSSRC)
+	binary.BigEndian.PutUint32(buf[ssrcLength:], f.MediaSSRC)
+	return feedbackHeaderLength, nil
+}
+
+// Unmarshal decodes the FeedbackHeader from the start of rawPacket, which
+// begins after the RTCP header
+func (f *FeedbackHeader) Unmarshal(rawPacket []byte) error {
+	if len(rawPacket) < feedbackHeaderLength {
+		return errPacketTooShort
+	}
+	f.SenderSSRC = binary.BigEndian.Uint32(rawPacket)
+	f.MediaSSRC = binary.BigEndian.Uint32(rawPacket[ssrcLength:])
+	return nil
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedbackHeader(t *testing.T) {
+	fh := FeedbackHeader{SenderSSRC: 0x902f9e2e, MediaSSRC: 0xbc5e9a40}
+	data, err := fh.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x90, 0x2f, 0x9e, 0x2e, 0xbc, 0x5e, 0x9a, 0x40}, data)
+
+	var decoded FeedbackHeader
+	assert.NoError(t, decoded.Unmarshal(data))
+	assert.Equal(t, fh, decoded)
+
+	_, err = fh.MarshalTo(make([]byte, feedbackHeaderLength-1))
+	assert.ErrorIs(t, err, errPacketTooShort)
+	assert.ErrorIs(t, decoded.Unmarshal(data[:feedbackHeaderLength-1]), errPacketTooShort)
+
+	t.Run("PictureLossIndication", func(t *testing.T) {
+		pli := PictureLossIndication{SenderSSRC: fh.SenderSSRC, MediaSSRC: fh.MediaSSRC}
+		assert.Equal(t, fh, pli.FeedbackHeader())
+
+		raw, err := pli.Marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, data, raw[headerLength:headerLength+feedbackHeaderLength])
+
+		var decoded PictureLossIndication
+		assert.NoError(t, decoded.Unmarshal(raw))
+		assert.Equal(t, pli, decoded)
+	})
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+// maxHistoryWindow is the largest retention window of a FeedbackHistory.
+// Sequence numbers further apart can not be ordered by serial number
+// arithmetic.
+const maxHistoryWindow = 1 << 15
+
+// A FeedbackHistory accumulates the feedback of successive
+// CCFeedbackReports, keyed by media SSRC and sequence number. Reports
+// usually overlap, as every report covers the packets received since a
+// point that may lie before the previous report; an overlapping sequence
+// number takes the feedback of the report ingested last.
+//
+// For every media source, only the window sequence numbers up to the
+// highest one reported are retained. Sequence numbers are compared using
+// serial number arithmetic, so the history continues across a wraparound.
+type FeedbackHistory struct {
+	window  int
+	streams map[uint32]*feedbackStream
+}
+
+type feedbackStream struct {
+	highest uint16
+	entries map[uint16]CCFeedbackMetricBlock
+}
+
+// NewFeedbackHistory returns an empty history retaining window sequence
+// numbers per media source. window is limited to the range 1 to 32768.
+func NewFeedbackHistory(window int) *FeedbackHistory {
+	if window < 1 {
+		window = 1
+	}
+	if window > maxHistoryWindow {
+		window = maxHistoryWindow
+	}
+	return &FeedbackHistory{
+		window:  window,
+		streams: make(map[uint32]*feedbackStream),
+	}
+}
+
+// Ingest records the feedback of every metric block of r, replacing the
+// feedback recorded for the same sequence numbers before. Sequence numbers
+// that fall outside the retention window are dropped.
+func (h *FeedbackHistory) Ingest(r *CCFeedbackReport) {
+	updated := make(map[uint32]*feedbackStream)
+	for _, block := range r.ReportBlocks {
+		if len(block.MetricBlocks) == 0 {
+			continue
+		}
+		end := block.BeginSequence + uint16(len(block.MetricBlocks)-1)
+		s, ok := h.streams[block.MediaSSRC]
+		if !ok {
+			s = &feedbackStream{highest: end, entries: make(map[uint16]CCFeedbackMetricBlock)}
+			h.streams[block.MediaSSRC] = s
+		} else if diff := end - s.highest; diff != 0 && diff < 1<<15 {
+			s.highest = end
+		}
+
+		for i, mb := range block.MetricBlocks {
+			s.entries[block.BeginSequence+uint16(i)] = mb
+		}
+		updated[block.MediaSSRC] = s
+	}
+
+	for _, s := range updated {
+		for seq := range s.entries {
+			if !h.retained(s, seq) {
+				delete(s.entries, seq)
+			}
+		}
+	}
+}
+
+// Query returns the feedback recorded for seq of the media source ssrc. The
+// boolean is false if no retained report covered seq.
+func (h *FeedbackHistory) Query(ssrc uint32, seq uint16) (CCFeedbackMetricBlock, bool) {
+	s, ok := h.streams[ssrc]
+	if !ok || !h.retained(s, seq) {
+		return CCFeedbackMetricBlock{}, false
+	}
+	mb, ok := s.entries[seq]
+	return mb, ok
+}
+
+// retained reports whether seq lies within the retention window of s
+func (h *FeedbackHistory) retained(s *feedbackStream, seq uint16) bool {
+	return int(s.highest-seq) < h.window
+}
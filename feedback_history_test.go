@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedbackHistory(t *testing.T) {
+	received := func(offset uint16) CCFeedbackMetricBlock {
+		return CCFeedbackMetricBlock{Received: true, ECN: ECNECT0, ArrivalTimeOffset: offset}
+	}
+	lost := CCFeedbackMetricBlock{}
+
+	t.Run("overlapping reports", func(t *testing.T) {
+		h := NewFeedbackHistory(100)
+		h.Ingest(&CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{received(40), lost, received(20), lost}},
+			{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{lost, lost}},
+		}})
+		// The second report overlaps 12 and 13, and learned that 13 arrived
+		// late.
+		h.Ingest(&CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 12, MetricBlocks: []CCFeedbackMetricBlock{received(60), received(50), lost, received(10)}},
+		}})
+
+		for _, test := range []struct {
+			SSRC  uint32
+			Seq   uint16
+			Want  CCFeedbackMetricBlock
+			Found bool
+		}{
+			{SSRC: 1, Seq: 9},
+			{SSRC: 1, Seq: 10, Want: received(40), Found: true},
+			{SSRC: 1, Seq: 11, Want: lost, Found: true},
+			{SSRC: 1, Seq: 12, Want: received(60), Found: true},
+			{SSRC: 1, Seq: 13, Want: received(50), Found: true},
+			{SSRC: 1, Seq: 14, Want: lost, Found: true},
+			{SSRC: 1, Seq: 15, Want: received(10), Found: true},
+			{SSRC: 1, Seq: 16},
+			{SSRC: 2, Seq: 11, Want: lost, Found: true},
+			{SSRC: 3, Seq: 10},
+		} {
+			mb, found := h.Query(test.SSRC, test.Seq)
+			assert.Equal(t, test.Found, found, "SSRC %d seq %d", test.SSRC, test.Seq)
+			assert.Equal(t, test.Want, mb, "SSRC %d seq %d", test.SSRC, test.Seq)
+		}
+	})
+
+	t.Run("retention window", func(t *testing.T) {
+		h := NewFeedbackHistory(4)
+		h.Ingest(&CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{received(1), received(2), received(3), received(4)}},
+		}})
+		h.Ingest(&CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 14, MetricBlocks: []CCFeedbackMetricBlock{lost, received(5)}},
+		}})
+		for seq := uint16(10); seq < 12; seq++ {
+			_, found := h.Query(1, seq)
+			assert.False(t, found, "seq %d", seq)
+		}
+		for seq := uint16(12); seq < 16; seq++ {
+			_, found := h.Query(1, seq)
+			assert.True(t, found, "seq %d", seq)
+		}
+
+		// A late report about old sequence numbers does not move the window
+		// back.
+		h.Ingest(&CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 8, MetricBlocks: []CCFeedbackMetricBlock{lost, lost, lost, lost}},
+		}})
+		_, found := h.Query(1, 10)
+		assert.False(t, found)
+		mb, found := h.Query(1, 15)
+		assert.True(t, found)
+		assert.Equal(t, received(5), mb)
+	})
+
+	t.Run("wraparound", func(t *testing.T) {
+		h := NewFeedbackHistory(8)
+		h.Ingest(&CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 0xFFFE, MetricBlocks: []CCFeedbackMetricBlock{received(4), lost}},
+			{MediaSSRC: 1, BeginSequence: 0, MetricBlocks: []CCFeedbackMetricBlock{received(2), received(1)}},
+		}})
+		for seq, want := range map[uint16]CCFeedbackMetricBlock{
+			0xFFFE: received(4),
+			0xFFFF: lost,
+			0:      received(2),
+			1:      received(1),
+		} {
+			mb, found := h.Query(1, seq)
+			assert.True(t, found, "seq %d", seq)
+			assert.Equal(t, want, mb, "seq %d", seq)
+		}
+		_, found := h.Query(1, 2)
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// A FeedbackWriter encodes a CCFeedbackReport to an io.Writer one report
+// block at a time, so very large reports do not have to be held in memory
+// as a []CCFeedbackReportBlock.
+//
+// The header at the front of the report holds its length, which is only
+// known once the last block has been written. If the io.Writer is also an
+// io.Seeker, blocks are written through as they are added and Close seeks
+// back to fill in the length. Any other io.Writer gets the report in a
+// single Write from Close, with the encoded blocks buffered until then.
+type FeedbackWriter struct {
+	w               io.Writer
+	seeker          io.Seeker
+	start           int64
+	buf             bytes.Buffer
+	reportTimestamp uint32
+	length          int
+	err             error
+}
+
+// NewFeedbackWriter starts a report from senderSSRC with the given report
+// timestamp on w. With a seekable writer the header is written right away,
+// and an error doing so is returned by the first WriteBlock or Close.
+func NewFeedbackWriter(w io.Writer, senderSSRC, reportTimestamp uint32) *FeedbackWriter {
+	f := &FeedbackWriter{
+		w:               w,
+		reportTimestamp: reportTimestamp,
+		length:          reportBlockOffset + reportTimestampLength,
+	}
+
+	header := make([]byte, reportBlockOffset)
+	h := Header{Count: FormatCCFB, Type: TypeTransportSpecificFeedback}
+	hData, err := h.Marshal()
+	if err != nil {
+		f.err = err
+		return f
+	}
+	copy(header, hData)
+	binary.BigEndian.PutUint32(header[headerLength:], senderSSRC)
+
+	if seeker, ok := w.(io.Seeker); ok {
+		if f.start, f.err = seeker.Seek(0, io.SeekCurrent); f.err != nil {
+			return f
+		}
+		f.seeker = seeker
+		_, f.err = w.Write(header)
+		return f
+	}
+
+	f.buf.Write(header)
+	return f
+}
+
+// WriteBlock adds block to the report.
+func (f *FeedbackWriter) WriteBlock(block CCFeedbackReportBlock) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	data, err := block.marshal()
+	if err != nil {
+		return err
+	}
+	if f.length+len(data) > maxPacketLength {
+		return errPacketTooLarge
+	}
+	f.length += len(data)
+
+	if f.seeker == nil {
+		f.buf.Write(data)
+		return nil
+	}
+	if _, err := f.w.Write(data); err != nil {
+		f.err = err
+		return err
+	}
+	return nil
+}
+
+// Close writes the report timestamp and the final length of the report.
+// The FeedbackWriter can not be used afterwards.
+func (f *FeedbackWriter) Close() error {
+	if f.err != nil {
+		return f.err
+	}
+	f.err = errWriterClosed
+
+	var timestamp [reportTimestampLength]byte
+	binary.BigEndian.PutUint32(timestamp[:], f.reportTimestamp)
+	length := uint16(f.length/4 - 1)
+
+	if f.seeker == nil {
+		f.buf.Write(timestamp[:])
+		binary.BigEndian.PutUint16(f.buf.Bytes()[2:], length)
+		_, err := f.w.Write(f.buf.Bytes())
+		return err
+	}
+
+	if _, err := f.w.Write(timestamp[:]); err != nil {
+		return err
+	}
+	if _, err := f.seeker.Seek(f.start+2, io.SeekStart); err != nil {
+		return err
+	}
+	var lengthData [2]byte
+	binary.BigEndian.PutUint16(lengthData[:], length)
+	if _, err := f.w.Write(lengthData[:]); err != nil {
+		return err
+	}
+	_, err := f.seeker.Seek(f.start+int64(f.length), io.SeekStart)
+	return err
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryFile is an in-memory io.WriteSeeker.
+type memoryFile struct {
+	data   []byte
+	offset int
+}
+
+func (m *memoryFile) Write(p []byte) (int, error) {
+	if end := m.offset + len(p); end > len(m.data) {
+		m.data = append(m.data, make([]byte, end-len(m.data))...)
+	}
+	n := copy(m.data[m.offset:], p)
+	m.offset += n
+	return n, nil
+}
+
+func (m *memoryFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		m.offset = int(offset)
+	case io.SeekCurrent:
+		m.offset += int(offset)
+	case io.SeekEnd:
+		m.offset = len(m.data) + int(offset)
+	}
+	return int64(m.offset), nil
+}
+
+// failingWriter is a seekable writer whose writes fail.
+type failingWriter struct{}
+
+var errFailingWriter = errors.New("write failed")
+
+func (failingWriter) Write([]byte) (int, error)      { return 0, errFailingWriter }
+func (failingWriter) Seek(int64, int) (int64, error) { return 0, nil }
+
+func TestFeedbackWriter(t *testing.T) {
+	blocks := []CCFeedbackReportBlock{
+		{
+			MediaSSRC:     1,
+			BeginSequence: 100,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 10},
+				{Received: false},
+				{Received: true, ECN: ECNCE, ArrivalTimeOffset: 5},
+			},
+		},
+		{
+			MediaSSRC:     2,
+			BeginSequence: 0xFFF0,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ArrivalTimeOffset: 1},
+				{Received: true, ArrivalTimeOffset: 2},
+			},
+		},
+		{
+			MediaSSRC: 3,
+		},
+	}
+	want, err := NewCCFeedbackReport(0x01020304, 0x05060708, blocks).Marshal()
+	assert.NoError(t, err)
+
+	t.Run("buffered", func(t *testing.T) {
+		var buf bytes.Buffer
+		f := NewFeedbackWriter(&buf, 0x01020304, 0x05060708)
+		for _, block := range blocks {
+			assert.NoError(t, f.WriteBlock(block))
+		}
+		assert.Zero(t, buf.Len())
+		assert.NoError(t, f.Close())
+		assert.Equal(t, want, buf.Bytes())
+	})
+
+	t.Run("seekable", func(t *testing.T) {
+		// Start after existing data to make sure the length is patched
+		// relative to the start of the report.
+		file := &memoryFile{}
+		_, _ = file.Write([]byte{0xAA, 0xBB, 0xCC, 0xDD})
+
+		f := NewFeedbackWriter(file, 0x01020304, 0x05060708)
+		for _, block := range blocks {
+			assert.NoError(t, f.WriteBlock(block))
+		}
+		assert.Len(t, file.data, 4+len(want)-reportTimestampLength)
+		assert.NoError(t, f.Close())
+		assert.Equal(t, append([]byte{0xAA, 0xBB, 0xCC, 0xDD}, want...), file.data)
+		assert.Equal(t, len(file.data), file.offset)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		f := NewFeedbackWriter(&bytes.Buffer{}, 1, 2)
+		assert.NoError(t, f.Close())
+		assert.ErrorIs(t, f.WriteBlock(blocks[0]), errWriterClosed)
+		assert.ErrorIs(t, f.Close(), errWriterClosed)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		f := NewFeedbackWriter(&bytes.Buffer{}, 1, 2)
+		block := CCFeedbackReportBlock{MetricBlocks: make([]CCFeedbackMetricBlock, MaxMetricBlocksPerReport)}
+		for i := 0; i < 7; i++ {
+			assert.NoError(t, f.WriteBlock(block))
+		}
+		assert.ErrorIs(t, f.WriteBlock(block), errPacketTooLarge)
+		assert.NoError(t, f.Close())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		f := NewFeedbackWriter(failingWriter{}, 1, 2)
+		assert.ErrorIs(t, f.WriteBlock(blocks[0]), errFailingWriter)
+		assert.ErrorIs(t, f.Close(), errFailingWriter)
+	})
+}
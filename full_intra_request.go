@@ -33,8 +33,9 @@ var _ Packet = (*FullIntraRequest)(nil)
 // Marshal encodes the FullIntraRequest
 func (p FullIntraRequest) Marshal() ([]byte, error) {
 	rawPacket := make([]byte, firOffset+(len(p.FIR)*8))
-	binary.BigEndian.PutUint32(rawPacket, p.SenderSSRC)
-	binary.BigEndian.PutUint32(rawPacket[4:], p.MediaSSRC)
+	if _, err := p.FeedbackHeader().MarshalTo(rawPacket); err != nil {
+		return nil, err
+	}
 	for i, fir := range p.FIR {
 		binary.BigEndian.PutUint32(rawPacket[firOffset+8*i:], fir.SSRC)
 		rawPacket[firOffset+8*i+4] = fir.SequenceNumber
@@ -72,8 +73,11 @@ func (p *FullIntraRequest) Unmarshal(rawPacket []byte) error {
 		return errBadLength
 	}
 
-	p.SenderSSRC = binary.BigEndian.Uint32(rawPacket[headerLength:])
-	p.MediaSSRC = binary.BigEndian.Uint32(rawPacket[headerLength+ssrcLength:])
+	var fh FeedbackHeader
+	if err := fh.Unmarshal(rawPacket[headerLength:]); err != nil {
+		return err
+	}
+	p.SenderSSRC, p.MediaSSRC = fh.SenderSSRC, fh.MediaSSRC
 	for i := headerLength + firOffset; i < (headerLength + int(h.Length*4)); i += 8 {
 		p.FIR = append(p.FIR, FIREntry{
 			binary.BigEndian.Uint32(rawPacket[i:]),
@@ -106,6 +110,11 @@ func (p *FullIntraRequest) String() string {
 	return out
 }
 
+// FeedbackHeader returns the SSRCs of the sender and the media source
+func (p FullIntraRequest) FeedbackHeader() FeedbackHeader {
+	return FeedbackHeader{SenderSSRC: p.SenderSSRC, MediaSSRC: p.MediaSSRC}
+}
+
 // DestinationSSRC returns an array of SSRC values that this packet refers to.
 func (p *FullIntraRequest) DestinationSSRC() []uint32 {
 	ssrcs := make([]uint32, 0, len(p.FIR))
@@ -114,3 +123,8 @@ func (p *FullIntraRequest) DestinationSSRC() []uint32 {
 	}
 	return ssrcs
 }
+
+// Kind returns KindFullIntraRequest.
+func (p *FullIntraRequest) Kind() PacketKind {
+	return KindFullIntraRequest
+}
@@ -93,6 +93,10 @@ func (g *Goodbye) Unmarshal(rawPacket []byte) error {
 		return errPacketTooShort
 	}
 
+	if err := header.Validate(len(rawPacket)); err != nil {
+		return err
+	}
+
 	g.Sources = make([]uint32, header.Count)
 
 	reasonOffset := int(headerLength + header.Count*ssrcLength)
@@ -114,6 +118,12 @@ func (g *Goodbye) Unmarshal(rawPacket []byte) error {
 			return errPacketTooShort
 		}
 
+		// Only the padding that aligns the reason may follow it, unless
+		// the packet itself is padded.
+		if !header.Padding && reasonEnd+getPadding(reasonEnd) != len(rawPacket) {
+			return errLengthMismatch
+		}
+
 		g.Reason = string(rawPacket[reasonOffset+1 : reasonEnd])
 	}
 
@@ -148,6 +158,11 @@ func (g *Goodbye) DestinationSSRC() []uint32 {
 	return out
 }
 
+// Kind returns KindGoodbye.
+func (g *Goodbye) Kind() PacketKind {
+	return KindGoodbye
+}
+
 func (g Goodbye) String() string {
 	out := "Goodbye\n"
 	for i, s := range g.Sources {
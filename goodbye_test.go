@@ -21,8 +21,8 @@ func TestGoodbyeUnmarshal(t *testing.T) {
 		{
 			Name: "valid",
 			Data: []byte{
-				// v=2, p=0, count=1, BYE, len=12
-				0x81, 0xcb, 0x00, 0x0c,
+				// v=2, p=0, count=1, BYE, len=2
+				0x81, 0xcb, 0x00, 0x02,
 				// ssrc=0x902f9e2e
 				0x90, 0x2f, 0x9e, 0x2e,
 				// len=3, text=FOO
@@ -36,8 +36,8 @@ func TestGoodbyeUnmarshal(t *testing.T) {
 		{
 			Name: "invalid octet count",
 			Data: []byte{
-				// v=2, p=0, count=1, BYE, len=12
-				0x81, 0xcb, 0x00, 0x0c,
+				// v=2, p=0, count=1, BYE, len=2
+				0x81, 0xcb, 0x00, 0x02,
 				// ssrc=0x902f9e2e
 				0x90, 0x2f, 0x9e, 0x2e,
 				// len=4, text=FOO
@@ -48,8 +48,8 @@ func TestGoodbyeUnmarshal(t *testing.T) {
 		{
 			Name: "wrong type",
 			Data: []byte{
-				// v=2, p=0, count=1, SDES, len=12
-				0x81, 0xca, 0x00, 0x0c,
+				// v=2, p=0, count=1, SDES, len=2
+				0x81, 0xca, 0x00, 0x02,
 				// ssrc=0x902f9e2e
 				0x90, 0x2f, 0x9e, 0x2e,
 				// len=3, text=FOO
@@ -60,8 +60,8 @@ func TestGoodbyeUnmarshal(t *testing.T) {
 		{
 			Name: "short reason",
 			Data: []byte{
-				// v=2, p=0, count=1, BYE, len=12
-				0x81, 0xcb, 0x00, 0x0c,
+				// v=2, p=0, count=1, BYE, len=2
+				0x81, 0xcb, 0x00, 0x02,
 				// ssrc=0x902f9e2e
 				0x90, 0x2f, 0x9e, 0x2e,
 				// len=3, text=F + padding
@@ -87,8 +87,8 @@ func TestGoodbyeUnmarshal(t *testing.T) {
 		{
 			Name: "bad count in header",
 			Data: []byte{
-				// v=2, p=0, count=2, BYE, len=8
-				0x82, 0xcb, 0x00, 0x0c,
+				// v=2, p=0, count=2, BYE, len=1
+				0x82, 0xcb, 0x00, 0x01,
 				// ssrc=0x902f9e2e
 				0x90, 0x2f, 0x9e, 0x2e,
 			},
@@ -97,14 +97,53 @@ func TestGoodbyeUnmarshal(t *testing.T) {
 		{
 			Name: "empty packet",
 			Data: []byte{
-				// v=2, p=0, count=0, BYE, len=4
-				0x80, 0xcb, 0x00, 0x04,
+				// v=2, p=0, count=0, BYE, len=0
+				0x80, 0xcb, 0x00, 0x00,
 			},
 			Want: Goodbye{
 				Sources: []uint32{},
 				Reason:  "",
 			},
 		},
+		{
+			Name: "reason with padding",
+			Data: []byte{
+				// v=2, p=0, count=1, BYE, len=3
+				0x81, 0xcb, 0x00, 0x03,
+				// ssrc=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+				// len=5, text=FOOBA + padding
+				0x05, 0x46, 0x4f, 0x4f,
+				0x42, 0x41, 0x00, 0x00,
+			},
+			Want: Goodbye{
+				Sources: []uint32{0x902f9e2e},
+				Reason:  "FOOBA",
+			},
+		},
+		{
+			Name: "trailing data after reason",
+			Data: []byte{
+				// v=2, p=0, count=1, BYE, len=3
+				0x81, 0xcb, 0x00, 0x03,
+				// ssrc=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+				// len=1, text=F + padding
+				0x01, 0x46, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+			},
+			WantError: errLengthMismatch,
+		},
+		{
+			Name: "length mismatch",
+			Data: []byte{
+				// v=2, p=0, count=1, BYE, len=3
+				0x81, 0xcb, 0x00, 0x03,
+				// ssrc=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+			},
+			WantError: errLengthMismatch,
+		},
 		{
 			Name:      "nil",
 			Data:      nil,
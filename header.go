@@ -69,6 +69,10 @@ type Header struct {
 	// the control information but are included in the length field.
 	Padding bool
 	// The number of reception reports, sources contained or FMT in this packet (depending on the Type)
+	//
+	// The header has no other reserved bits. Packet types whose count field
+	// is reserved, such as ExtendedReport, ignore it when decoding and write
+	// 0 when encoding, so reserved bits set by the sender are not preserved.
 	Count uint8
 	// The RTCP packet type for this packet
 	Type PacketType
@@ -79,6 +83,10 @@ type Header struct {
 
 const (
 	headerLength = 4
+	// maxPacketLength is the size of the largest RTCP packet, whose header
+	// length field holds 0xFFFF
+	maxPacketLength = (0xFFFF + 1) * 4
+
 	versionShift = 6
 	versionMask  = 0x3
 	paddingShift = 5
@@ -145,3 +153,37 @@ func (h *Header) Unmarshal(rawPacket []byte) error {
 
 	return nil
 }
+
+// ParsePacketType returns the packet type and the count or feedback message
+// type (FMT) of the RTCP packet at the start of rawPacket. Only the first two
+// bytes are read and the version is checked; the length and the body are not
+// looked at. This is cheaper than Unmarshal when deciding how to route a
+// packet.
+func ParsePacketType(rawPacket []byte) (uint8, uint8, error) {
+	if len(rawPacket) < 2 {
+		return 0, 0, errPacketTooShort
+	}
+
+	if version := rawPacket[0] >> versionShift & versionMask; version != rtpVersion {
+		return 0, 0, errBadVersion
+	}
+
+	return rawPacket[1], rawPacket[0] >> countShift & countMask, nil
+}
+
+// Validate checks that the Header describes a packet of rawLen bytes: the
+// count must fit into 5 bits and (Length+1)*4 must equal rawLen. Only the
+// length is known here, so the padding octet is not inspected: packet types
+// that allow padding check it against rawLen in their own Unmarshal. The
+// version is not retained by Header and is checked by Unmarshal instead.
+func (h Header) Validate(rawLen int) error {
+	if h.Count > countMax {
+		return errInvalidHeader
+	}
+
+	if (int(h.Length)+1)*4 != rawLen {
+		return errLengthMismatch
+	}
+
+	return nil
+}
@@ -116,3 +116,109 @@ func TestHeaderRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestHeaderValidate(t *testing.T) {
+	for _, test := range []struct {
+		Name      string
+		Header    Header
+		RawLen    int
+		WantError error
+	}{
+		{
+			Name:   "valid",
+			Header: Header{Count: 1, Type: TypeReceiverReport, Length: 7},
+			RawLen: 32,
+		},
+		{
+			Name:      "invalid count",
+			Header:    Header{Count: 32, Type: TypeReceiverReport, Length: 7},
+			RawLen:    32,
+			WantError: errInvalidHeader,
+		},
+		{
+			Name:      "length mismatch",
+			Header:    Header{Count: 1, Type: TypeReceiverReport, Length: 7},
+			RawLen:    28,
+			WantError: errLengthMismatch,
+		},
+		{
+			Name:   "max length",
+			Header: Header{Type: TypeReceiverReport, Length: 0xFFFF},
+			RawLen: 0x40000,
+		},
+	} {
+		if got, want := test.Header.Validate(test.RawLen), test.WantError; !errors.Is(got, want) {
+			t.Errorf("Validate %q: err = %v, want %v", test.Name, got, want)
+		}
+	}
+}
+
+func TestParsePacketType(t *testing.T) {
+	for _, test := range []struct {
+		Name      string
+		Data      []byte
+		WantType  uint8
+		WantFMT   uint8
+		WantError error
+	}{
+		{
+			Name: "application defined",
+			Data: []byte{
+				// v=2, p=0, subtype=3, APP, len=3
+				0x83, 0xcc, 0x00, 0x03,
+				// SSRC=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+				// name="TEST"
+				0x54, 0x45, 0x53, 0x54,
+				// data
+				0x01, 0x02, 0x03, 0x04,
+			},
+			WantType: uint8(TypeApplicationDefined),
+			WantFMT:  3,
+		},
+		{
+			Name: "ccfeedback",
+			Data: []byte{
+				// v=2, p=0, FMT=11, TSFB, len=2
+				0x8b, 0xcd, 0x00, 0x02,
+				// sender SSRC=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+				// report timestamp
+				0x00, 0x00, 0x00, 0x01,
+			},
+			WantType: uint8(TypeTransportSpecificFeedback),
+			WantFMT:  FormatCCFB,
+		},
+		{
+			Name:     "header only",
+			Data:     []byte{0xa1, 0xce},
+			WantType: uint8(TypePayloadSpecificFeedback),
+			WantFMT:  FormatPLI,
+		},
+		{
+			Name:      "bad version",
+			Data:      []byte{0x4b, 0xcd, 0x00, 0x02},
+			WantError: errBadVersion,
+		},
+		{
+			Name:      "too short",
+			Data:      []byte{0x8b},
+			WantError: errPacketTooShort,
+		},
+	} {
+		gotType, gotFMT, err := ParsePacketType(test.Data)
+		if got, want := err, test.WantError; !errors.Is(got, want) {
+			t.Fatalf("ParsePacketType %q: err = %v, want %v", test.Name, got, want)
+		}
+		if gotType != test.WantType || gotFMT != test.WantFMT {
+			t.Fatalf("ParsePacketType %q: got (%d, %d), want (%d, %d)", test.Name, gotType, gotFMT, test.WantType, test.WantFMT)
+		}
+	}
+
+	data := []byte{0x8b, 0xcd, 0x00, 0x02}
+	if allocs := testing.AllocsPerRun(100, func() {
+		_, _, _ = ParsePacketType(data)
+	}); allocs != 0 {
+		t.Fatalf("ParsePacketType allocates %v times, want 0", allocs)
+	}
+}
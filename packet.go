@@ -3,6 +3,8 @@
 
 package rtcp
 
+import "fmt"
+
 // Packet represents an RTCP packet, a protocol used for out-of-band statistics and control information for an RTP session
 type Packet interface {
 	// DestinationSSRC returns an array of SSRC values that this packet refers to.
@@ -13,12 +15,50 @@ type Packet interface {
 	MarshalSize() int
 }
 
+// KindedPacket is implemented by every Packet of this package. Switching on
+// Kind dispatches on the concrete type without a type switch. It is kept out
+// of Packet so that implementations outside this package remain Packets.
+type KindedPacket interface {
+	Packet
+
+	// Kind returns the kind of the packet.
+	Kind() PacketKind
+}
+
+// PacketKind identifies the concrete type of a KindedPacket. Unlike
+// PacketType it tells apart the feedback messages that share a payload type.
+type PacketKind uint8
+
+// Kinds of the Packet implementations of this package
+const (
+	// KindRaw is a RawPacket, used for packet types that are not implemented
+	KindRaw PacketKind = iota
+	KindCompound
+	KindSenderReport
+	KindReceiverReport
+	KindSourceDescription
+	KindGoodbye
+	KindExtendedReport
+	KindTransportLayerNack
+	KindRapidResynchronizationRequest
+	KindTransportLayerCC
+	KindCCFeedback
+	KindPictureLossIndication
+	KindSliceLossIndication
+	KindFullIntraRequest
+	KindReceiverEstimatedMaximumBitrate
+)
+
 // Unmarshal takes an entire udp datagram (which may consist of multiple RTCP packets) and
 // returns the unmarshaled packets it contains.
 //
 // If this is a reduced-size RTCP packet a feedback packet (Goodbye, SliceLossIndication, etc)
 // will be returned. Otherwise, the underlying type of the returned packet will be
 // CompoundPacket.
+//
+// Packets whose type is unknown or not implemented are returned as RawPacket,
+// so that extensions do not prevent decoding the rest of a compound packet.
+// Use UnmarshalStrict to reject packet types that are not RTCP types.
 func Unmarshal(rawData []byte) ([]Packet, error) {
 	var packets []Packet
 	for len(rawData) != 0 {
@@ -41,6 +81,76 @@ func Unmarshal(rawData []byte) ([]Packet, error) {
 	}
 }
 
+// UnmarshalStrict decodes rawData like Unmarshal, but fails with an
+// *UnsupportedPacketError if a packet has a payload type that is not one of
+// the RTCP packet types of RFC 3550, 3611 and 4585. Packets of those types
+// that are not implemented, such as application-defined packets or unknown
+// feedback messages, are still returned as RawPacket.
+func UnmarshalStrict(rawData []byte) ([]Packet, error) {
+	packets, err := Unmarshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range packets {
+		raw, ok := p.(*RawPacket)
+		if !ok {
+			continue
+		}
+		if h := raw.Header(); h.Type < TypeSenderReport || h.Type > TypeExtendedReport {
+			return nil, &UnsupportedPacketError{PayloadType: uint8(h.Type), FMT: h.Count}
+		}
+	}
+	return packets, nil
+}
+
+// UnmarshalCompoundLenient decodes a compound datagram like Unmarshal, but
+// continues past a packet that fails to decode, skipping it by its header
+// length, and returns the errors of all such packets along with the packets
+// that did decode. This suits monitoring tools that prefer partial data over
+// none. The first packet must decode and be a SenderReport or ReceiverReport,
+// otherwise nothing is returned but its error. Decoding stops at a header
+// that can not be read or whose length exceeds the datagram, as the next
+// packet can not be found.
+func UnmarshalCompoundLenient(rawPacket []byte) ([]Packet, []error) {
+	if len(rawPacket) == 0 {
+		return nil, []error{errInvalidHeader}
+	}
+
+	var packets []Packet
+	var errs []error
+	for offset := 0; offset < len(rawPacket); {
+		var h Header
+		if err := h.Unmarshal(rawPacket[offset:]); err != nil {
+			errs = append(errs, fmt.Errorf("packet %d at offset %d: %w", len(packets)+len(errs), offset, err))
+			break
+		}
+		packetLen := (int(h.Length) + 1) * 4
+		if packetLen > len(rawPacket)-offset {
+			errs = append(errs, fmt.Errorf("packet %d at offset %d: %w", len(packets)+len(errs), offset, errPacketTooShort))
+			break
+		}
+
+		p, _, err := unmarshal(rawPacket[offset : offset+packetLen])
+		if offset == 0 {
+			if err != nil {
+				return nil, []error{fmt.Errorf("packet 0 at offset 0: %w", err)}
+			}
+			switch p.(type) {
+			case *SenderReport, *ReceiverReport:
+			default:
+				return nil, []error{errBadFirstPacket}
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("packet %d at offset %d: %w", len(packets)+len(errs), offset, err))
+		} else {
+			packets = append(packets, p)
+		}
+		offset += packetLen
+	}
+	return packets, errs
+}
+
 // Marshal takes an array of Packets and serializes them to a single buffer
 func Marshal(packets []Packet) ([]byte, error) {
 	out := make([]byte, 0)
@@ -54,6 +164,73 @@ func Marshal(packets []Packet) ([]byte, error) {
 	return out, nil
 }
 
+// SplitCompound splits a datagram of concatenated RTCP packets into the raw
+// bytes of each packet, advancing by each Header's Length. The lengths must
+// exactly consume rawData. Only the last packet may have its padding bit set,
+// as RFC 3550 Section 6.4.1 requires; its last octet holds the padding count,
+// which must be non-zero and fit within the packet.
+func SplitCompound(rawData []byte) ([][]byte, error) {
+	if len(rawData) == 0 {
+		return nil, errInvalidHeader
+	}
+
+	var packets [][]byte
+	for len(rawData) != 0 {
+		var h Header
+		if err := h.Unmarshal(rawData); err != nil {
+			return nil, err
+		}
+
+		packetLen := (int(h.Length) + 1) * 4
+		if packetLen > len(rawData) {
+			return nil, errPacketTooShort
+		}
+
+		if h.Padding {
+			if packetLen != len(rawData) {
+				return nil, errWrongPadding
+			}
+			paddingLen := int(rawData[packetLen-1])
+			if paddingLen == 0 || paddingLen > packetLen-headerLength {
+				return nil, errLengthMismatch
+			}
+		}
+
+		packets = append(packets, rawData[:packetLen])
+		rawData = rawData[packetLen:]
+	}
+	return packets, nil
+}
+
+// CountRTCPPackets returns the number of RTCP packets in a compound datagram,
+// validating the framing the same way SplitCompound does.
+func CountRTCPPackets(rawData []byte) (int, error) {
+	packets, err := SplitCompound(rawData)
+	if err != nil {
+		return 0, err
+	}
+	return len(packets), nil
+}
+
+// UnsupportedPacketError is returned by UnmarshalStrict for a packet whose
+// payload type is not one of the RTCP packet types known to this package.
+type UnsupportedPacketError struct {
+	PayloadType uint8
+
+	// FMT is the count or feedback message type field of the header
+	FMT uint8
+}
+
+func (e *UnsupportedPacketError) Error() string {
+	return fmt.Sprintf("%v: payload type %d, fmt %d", ErrUnsupportedPacket, e.PayloadType, e.FMT)
+}
+
+// Unwrap returns ErrUnsupportedPacket, the sentinel shared by all
+// UnsupportedPacketErrors
+func (e *UnsupportedPacketError) Unwrap() error {
+	return ErrUnsupportedPacket
+}
+
 // unmarshal is a factory which pulls the first RTCP packet from a bytestream,
 // and returns it's parsed representation, and the amount of data that was processed.
 func unmarshal(rawData []byte) (packet Packet, bytesprocessed int, err error) {
@@ -122,3 +299,38 @@ func unmarshal(rawData []byte) (packet Packet, bytesprocessed int, err error) {
 
 	return packet, bytesprocessed, err
 }
+
+// UniqueDestinationSSRC returns the union of the DestinationSSRC values of
+// every packet, in the order they are first seen and without duplicates.
+func UniqueDestinationSSRC(packets []Packet) []uint32 {
+	seen := make(map[uint32]struct{})
+	out := []uint32{}
+	for _, p := range packets {
+		for _, ssrc := range p.DestinationSSRC() {
+			if _, ok := seen[ssrc]; ok {
+				continue
+			}
+			seen[ssrc] = struct{}{}
+			out = append(out, ssrc)
+		}
+	}
+	return out
+}
+
+// FilterByType returns the packets of type T, in order. T is usually a
+// pointer type such as *SenderReport, as Unmarshal returns pointers.
+func FilterByType[T Packet](packets []Packet) []T {
+	var out []T
+	for _, p := range packets {
+		if t, ok := p.(T); ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// FilterCCFeedback returns the Congestion Control Feedback Reports among
+// packets, in order.
+func FilterCCFeedback(packets []Packet) []*CCFeedbackReport {
+	return FilterByType[*CCFeedbackReport](packets)
+}
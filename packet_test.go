@@ -130,3 +130,333 @@ func TestInvalidHeaderLength(t *testing.T) {
 		t.Fatalf("Unmarshal(nil) err = %v, want %v", got, want)
 	}
 }
+
+func TestUnmarshalUnsupportedPacket(t *testing.T) {
+	data := append(realPacket(), []byte{
+		// v=2, p=0, count=3, PT=199 (not an RTCP type known here), len=1
+		0x83, 0xc7, 0x00, 0x01,
+		0x01, 0x02, 0x03, 0x04,
+	}...)
+
+	// By default unknown packets are carried along with the others.
+	want, err := Unmarshal(realPacket())
+	assert.NoError(t, err)
+	packets, err := Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, append(want, &RawPacket{0x83, 0xc7, 0x00, 0x01, 0x01, 0x02, 0x03, 0x04}), packets)
+
+	_, err = UnmarshalStrict(data)
+	var unsupported *UnsupportedPacketError
+	assert.ErrorAs(t, err, &unsupported)
+	assert.ErrorIs(t, err, ErrUnsupportedPacket)
+	assert.Equal(t, &UnsupportedPacketError{PayloadType: 199, FMT: 3}, unsupported)
+
+	packets, err = UnmarshalStrict(realPacket())
+	assert.NoError(t, err)
+	assert.Equal(t, want, packets)
+
+	// Application-defined packets are known, if not implemented.
+	packets, err = UnmarshalStrict([]byte{0x81, 0xcc, 0x00, 0x00})
+	assert.NoError(t, err)
+	assert.Equal(t, []Packet{&RawPacket{0x81, 0xcc, 0x00, 0x00}}, packets)
+}
+
+func TestUnmarshalCompoundLenient(t *testing.T) {
+	want, err := Unmarshal(realPacket())
+	assert.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		packets, errs := UnmarshalCompoundLenient(realPacket())
+		assert.Empty(t, errs)
+		assert.Equal(t, want, packets)
+	})
+
+	t.Run("corrupt middle packet", func(t *testing.T) {
+		data := realPacket()
+		// The Goodbye claims two sources but only has room for one.
+		data[84] = 0x82
+		_, err := Unmarshal(data)
+		assert.Error(t, err)
+
+		packets, errs := UnmarshalCompoundLenient(data)
+		if assert.Len(t, errs, 1) {
+			assert.Contains(t, errs[0].Error(), "packet 2 at offset 84")
+		}
+		assert.Equal(t, []Packet{want[0], want[1], want[3], want[4]}, packets)
+	})
+
+	t.Run("unknown middle packet", func(t *testing.T) {
+		data := realPacket()
+		data[85] = 0xc7
+		packets, errs := UnmarshalCompoundLenient(data)
+		assert.Empty(t, errs)
+		if assert.Len(t, packets, 5) {
+			assert.IsType(t, &RawPacket{}, packets[2])
+		}
+	})
+
+	t.Run("truncated last packet", func(t *testing.T) {
+		data := realPacket()
+		packets, errs := UnmarshalCompoundLenient(data[:len(data)-4])
+		if assert.Len(t, errs, 1) {
+			assert.ErrorIs(t, errs[0], errPacketTooShort)
+		}
+		assert.Equal(t, want[:4], packets)
+	})
+
+	t.Run("corrupt first packet", func(t *testing.T) {
+		data := realPacket()
+		// The Receiver Report claims two reception reports.
+		data[0] = 0x82
+		packets, errs := UnmarshalCompoundLenient(data)
+		assert.Nil(t, packets)
+		assert.Len(t, errs, 1)
+	})
+
+	t.Run("first packet not a report", func(t *testing.T) {
+		packets, errs := UnmarshalCompoundLenient(realPacket()[84:])
+		assert.Nil(t, packets)
+		assert.Equal(t, []error{errBadFirstPacket}, errs)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		packets, errs := UnmarshalCompoundLenient(nil)
+		assert.Nil(t, packets)
+		assert.Equal(t, []error{errInvalidHeader}, errs)
+	})
+}
+
+func TestUniqueDestinationSSRC(t *testing.T) {
+	packets := []Packet{
+		&SenderReport{
+			SSRC: 1,
+			Reports: []ReceptionReport{
+				{SSRC: 2},
+				{SSRC: 3},
+			},
+		},
+		&CCFeedbackReport{
+			SenderSSRC: 1,
+			ReportBlocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 3},
+				{MediaSSRC: 4},
+				{MediaSSRC: 3},
+				{MediaSSRC: 2},
+			},
+		},
+	}
+
+	assert.Equal(t, []uint32{2, 3, 1, 4}, UniqueDestinationSSRC(packets))
+	assert.Equal(t, []uint32{}, UniqueDestinationSSRC(nil))
+}
+
+func TestFilterByType(t *testing.T) {
+	first := &CCFeedbackReport{
+		SenderSSRC: 1,
+		ReportBlocks: []CCFeedbackReportBlock{{
+			MediaSSRC:    2,
+			MetricBlocks: []CCFeedbackMetricBlock{{Received: true, ArrivalTimeOffset: 10}, {}},
+		}},
+		ReportTimestamp: 100,
+	}
+	second := &CCFeedbackReport{
+		SenderSSRC: 1,
+		ReportBlocks: []CCFeedbackReportBlock{{
+			MediaSSRC:     3,
+			BeginSequence: 7,
+			MetricBlocks:  []CCFeedbackMetricBlock{{}, {Received: true, ECN: ECNCE}},
+		}},
+		ReportTimestamp: 200,
+	}
+	raw, err := Marshal([]Packet{
+		&SenderReport{SSRC: 1},
+		&SourceDescription{Chunks: []SourceDescriptionChunk{
+			{Source: 1, Items: []SourceDescriptionItem{{Type: SDESCNAME, Text: "cname"}}},
+		}},
+		first,
+		second,
+	})
+	assert.NoError(t, err)
+	packets, err := Unmarshal(raw)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []*CCFeedbackReport{first, second}, FilterCCFeedback(packets))
+	assert.Equal(t, []*SenderReport{{SSRC: 1}}, FilterByType[*SenderReport](packets))
+	assert.Len(t, FilterByType[*SourceDescription](packets), 1)
+	assert.Empty(t, FilterByType[*Goodbye](packets))
+	assert.Empty(t, FilterCCFeedback(nil))
+}
+
+func TestSplitCompound(t *testing.T) {
+	data := realPacket()
+	packets, err := SplitCompound(data)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{
+		data[0:32],
+		data[32:84],
+		data[84:92],
+		data[92:104],
+		data[104:116],
+	}, packets)
+
+	count, err := CountRTCPPackets(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	for _, test := range []struct {
+		Name      string
+		Data      []byte
+		WantError error
+	}{
+		{
+			Name:      "empty",
+			Data:      []byte{},
+			WantError: errInvalidHeader,
+		},
+		{
+			Name:      "truncated",
+			Data:      data[:len(data)-4],
+			WantError: errPacketTooShort,
+		},
+		{
+			Name:      "trailing bytes",
+			Data:      append(append([]byte{}, data...), 0x80),
+			WantError: errPacketTooShort,
+		},
+		{
+			Name: "valid padding",
+			Data: []byte{
+				// v=2, p=1, count=0, RR, len=2
+				0xa0, 0xc9, 0x00, 0x02,
+				// ssrc=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+				// padding
+				0x00, 0x00, 0x00, 0x04,
+			},
+		},
+		{
+			Name: "padding exceeds packet",
+			Data: []byte{
+				// v=2, p=1, count=0, RR, len=2
+				0xa0, 0xc9, 0x00, 0x02,
+				// ssrc=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+				// padding
+				0x00, 0x00, 0x00, 0x09,
+			},
+			WantError: errLengthMismatch,
+		},
+		{
+			Name: "zero padding",
+			Data: []byte{
+				// v=2, p=1, count=0, RR, len=1
+				0xa0, 0xc9, 0x00, 0x01,
+				// ssrc=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x00,
+			},
+			WantError: errLengthMismatch,
+		},
+		{
+			Name: "padding before last packet",
+			Data: []byte{
+				// v=2, p=1, count=0, RR, len=2
+				0xa0, 0xc9, 0x00, 0x02,
+				// ssrc=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+				// padding
+				0x00, 0x00, 0x00, 0x04,
+				// v=2, p=0, count=0, RR, len=1
+				0x80, 0xc9, 0x00, 0x01,
+				// ssrc=0x902f9e2e
+				0x90, 0x2f, 0x9e, 0x2e,
+			},
+			WantError: errWrongPadding,
+		},
+	} {
+		_, err := CountRTCPPackets(test.Data)
+		assert.ErrorIs(t, err, test.WantError, test.Name)
+	}
+}
+
+func TestPacketHeader(t *testing.T) {
+	for _, test := range []struct {
+		Packet interface {
+			Packet
+			Header() Header
+		}
+		Type  PacketType
+		Count uint8
+	}{
+		{Packet: &SenderReport{SSRC: 1, Reports: []ReceptionReport{{SSRC: 2}}}, Type: TypeSenderReport, Count: 1},
+		{Packet: &ReceiverReport{SSRC: 1}, Type: TypeReceiverReport},
+		{Packet: NewCNAMESourceDescription(1, "cname"), Type: TypeSourceDescription, Count: 1},
+		{Packet: &Goodbye{Sources: []uint32{1, 2}, Reason: "bye"}, Type: TypeGoodbye, Count: 2},
+		{Packet: &TransportLayerNack{Nacks: []NackPair{{PacketID: 1}}}, Type: TypeTransportSpecificFeedback, Count: FormatTLN},
+		{Packet: &RapidResynchronizationRequest{}, Type: TypeTransportSpecificFeedback, Count: FormatRRR},
+		{Packet: &CCFeedbackReport{}, Type: TypeTransportSpecificFeedback, Count: FormatCCFB},
+		{Packet: &SliceLossIndication{SLI: []SLIEntry{{}}}, Type: TypeTransportSpecificFeedback, Count: FormatSLI},
+		{Packet: &PictureLossIndication{}, Type: TypePayloadSpecificFeedback, Count: FormatPLI},
+		{Packet: &FullIntraRequest{FIR: []FIREntry{{}}}, Type: TypePayloadSpecificFeedback, Count: FormatFIR},
+		{Packet: &ReceiverEstimatedMaximumBitrate{SSRCs: []uint32{1}}, Type: TypePayloadSpecificFeedback, Count: FormatREMB},
+		{Packet: &ExtendedReport{Reports: []ReportBlock{&ReceiverReferenceTimeReportBlock{}}}, Type: TypeExtendedReport},
+		{Packet: &RawPacket{0x81, 0xcc, 0x00, 0x00}, Type: TypeApplicationDefined, Count: 1},
+	} {
+		h := test.Packet.Header()
+		assert.Equal(t, test.Type, h.Type, "%T", test.Packet)
+		assert.Equal(t, test.Count, h.Count, "%T", test.Packet)
+
+		// The header must describe the marshaled packet.
+		data, err := test.Packet.Marshal()
+		assert.NoError(t, err, "%T", test.Packet)
+		var wire Header
+		assert.NoError(t, wire.Unmarshal(data), "%T", test.Packet)
+		assert.Equal(t, wire, h, "%T", test.Packet)
+	}
+}
+
+func TestPacketKind(t *testing.T) {
+	for _, test := range []struct {
+		Packet KindedPacket
+		Kind   PacketKind
+	}{
+		{Packet: &SenderReport{SSRC: 1}, Kind: KindSenderReport},
+		{Packet: &ReceiverReport{SSRC: 1}, Kind: KindReceiverReport},
+		{Packet: NewCNAMESourceDescription(1, "cname"), Kind: KindSourceDescription},
+		{Packet: &Goodbye{Sources: []uint32{1}}, Kind: KindGoodbye},
+		{Packet: &ExtendedReport{Reports: []ReportBlock{&ReceiverReferenceTimeReportBlock{}}}, Kind: KindExtendedReport},
+		{Packet: &TransportLayerNack{Nacks: []NackPair{{PacketID: 1}}}, Kind: KindTransportLayerNack},
+		{Packet: &RapidResynchronizationRequest{}, Kind: KindRapidResynchronizationRequest},
+		{Packet: &TransportLayerCC{
+			Header:       Header{Count: FormatTCC, Type: TypeTransportSpecificFeedback, Length: 5, Padding: true},
+			PacketChunks: []PacketStatusChunk{&RunLengthChunk{PacketStatusSymbol: TypeTCCPacketNotReceived, RunLength: 1}},
+		}, Kind: KindTransportLayerCC},
+		{Packet: &CCFeedbackReport{}, Kind: KindCCFeedback},
+		{Packet: &PictureLossIndication{}, Kind: KindPictureLossIndication},
+		{Packet: &SliceLossIndication{SLI: []SLIEntry{{}}}, Kind: KindSliceLossIndication},
+		{Packet: &FullIntraRequest{FIR: []FIREntry{{}}}, Kind: KindFullIntraRequest},
+		{Packet: &ReceiverEstimatedMaximumBitrate{SSRCs: []uint32{1}}, Kind: KindReceiverEstimatedMaximumBitrate},
+		{Packet: &RawPacket{0x81, 0xcc, 0x00, 0x00}, Kind: KindRaw},
+	} {
+		assert.Equal(t, test.Kind, test.Packet.Kind(), "%T", test.Packet)
+
+		// Decoding yields a packet of the same kind. SliceLossIndication is
+		// marshaled as transport layer feedback, but only decoded as payload
+		// specific feedback.
+		if _, ok := test.Packet.(*SliceLossIndication); ok {
+			continue
+		}
+		data, err := test.Packet.Marshal()
+		assert.NoError(t, err, "%T", test.Packet)
+		decoded, err := Unmarshal(data)
+		assert.NoError(t, err, "%T", test.Packet)
+		if assert.Len(t, decoded, 1, "%T", test.Packet) {
+			kinded, ok := decoded[0].(KindedPacket)
+			if assert.True(t, ok, "%T", decoded[0]) {
+				assert.Equal(t, test.Kind, kinded.Kind(), "%T", test.Packet)
+			}
+		}
+	}
+
+	compound := CompoundPacket{&ReceiverReport{}, NewCNAMESourceDescription(1, "cname")}
+	assert.Equal(t, KindCompound, compound.Kind())
+}
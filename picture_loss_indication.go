@@ -3,10 +3,7 @@
 
 package rtcp
 
-import (
-	"encoding/binary"
-	"fmt"
-)
+import "fmt"
 
 // The PictureLossIndication packet informs the encoder about the loss of an undefined amount of coded video data belonging to one or more pictures
 type PictureLossIndication struct {
@@ -32,8 +29,9 @@ func (p PictureLossIndication) Marshal() ([]byte, error) {
 	rawPacket := make([]byte, p.MarshalSize())
 	packetBody := rawPacket[headerLength:]
 
-	binary.BigEndian.PutUint32(packetBody, p.SenderSSRC)
-	binary.BigEndian.PutUint32(packetBody[4:], p.MediaSSRC)
+	if _, err := p.FeedbackHeader().MarshalTo(packetBody); err != nil {
+		return nil, err
+	}
 
 	h := Header{
 		Count:  FormatPLI,
@@ -64,8 +62,11 @@ func (p *PictureLossIndication) Unmarshal(rawPacket []byte) error {
 		return errWrongType
 	}
 
-	p.SenderSSRC = binary.BigEndian.Uint32(rawPacket[headerLength:])
-	p.MediaSSRC = binary.BigEndian.Uint32(rawPacket[headerLength+ssrcLength:])
+	var fh FeedbackHeader
+	if err := fh.Unmarshal(rawPacket[headerLength:]); err != nil {
+		return err
+	}
+	p.SenderSSRC, p.MediaSSRC = fh.SenderSSRC, fh.MediaSSRC
 	return nil
 }
 
@@ -87,7 +88,17 @@ func (p *PictureLossIndication) String() string {
 	return fmt.Sprintf("PictureLossIndication %x %x", p.SenderSSRC, p.MediaSSRC)
 }
 
+// FeedbackHeader returns the SSRCs of the sender and the media source
+func (p PictureLossIndication) FeedbackHeader() FeedbackHeader {
+	return FeedbackHeader{SenderSSRC: p.SenderSSRC, MediaSSRC: p.MediaSSRC}
+}
+
 // DestinationSSRC returns an array of SSRC values that this packet refers to.
 func (p *PictureLossIndication) DestinationSSRC() []uint32 {
 	return []uint32{p.MediaSSRC}
 }
+
+// Kind returns KindPictureLossIndication.
+func (p *PictureLossIndication) Kind() PacketKind {
+	return KindPictureLossIndication
+}
@@ -3,10 +3,7 @@
 
 package rtcp
 
-import (
-	"encoding/binary"
-	"fmt"
-)
+import "fmt"
 
 // The RapidResynchronizationRequest packet informs the encoder about the loss of an undefined amount of coded video data belonging to one or more pictures
 type RapidResynchronizationRequest struct {
@@ -24,7 +21,6 @@ type RapidResynchronisationRequest = RapidResynchronizationRequest
 const (
 	rrrLength       = 2
 	rrrHeaderLength = ssrcLength * 2
-	rrrMediaOffset  = 4
 )
 
 // Marshal encodes the RapidResynchronizationRequest in binary
@@ -38,8 +34,9 @@ func (p RapidResynchronizationRequest) Marshal() ([]byte, error) {
 	rawPacket := make([]byte, p.MarshalSize())
 	packetBody := rawPacket[headerLength:]
 
-	binary.BigEndian.PutUint32(packetBody, p.SenderSSRC)
-	binary.BigEndian.PutUint32(packetBody[rrrMediaOffset:], p.MediaSSRC)
+	if _, err := p.FeedbackHeader().MarshalTo(packetBody); err != nil {
+		return nil, err
+	}
 
 	hData, err := p.Header().Marshal()
 	if err != nil {
@@ -65,8 +62,11 @@ func (p *RapidResynchronizationRequest) Unmarshal(rawPacket []byte) error {
 		return errWrongType
 	}
 
-	p.SenderSSRC = binary.BigEndian.Uint32(rawPacket[headerLength:])
-	p.MediaSSRC = binary.BigEndian.Uint32(rawPacket[headerLength+ssrcLength:])
+	var fh FeedbackHeader
+	if err := fh.Unmarshal(rawPacket[headerLength:]); err != nil {
+		return err
+	}
+	p.SenderSSRC, p.MediaSSRC = fh.SenderSSRC, fh.MediaSSRC
 	return nil
 }
 
@@ -84,11 +84,21 @@ func (p *RapidResynchronizationRequest) Header() Header {
 	}
 }
 
+// FeedbackHeader returns the SSRCs of the sender and the media source
+func (p RapidResynchronizationRequest) FeedbackHeader() FeedbackHeader {
+	return FeedbackHeader{SenderSSRC: p.SenderSSRC, MediaSSRC: p.MediaSSRC}
+}
+
 // DestinationSSRC returns an array of SSRC values that this packet refers to.
 func (p *RapidResynchronizationRequest) DestinationSSRC() []uint32 {
 	return []uint32{p.MediaSSRC}
 }
 
+// Kind returns KindRapidResynchronizationRequest.
+func (p *RapidResynchronizationRequest) Kind() PacketKind {
+	return KindRapidResynchronizationRequest
+}
+
 func (p *RapidResynchronizationRequest) String() string {
 	return fmt.Sprintf("RapidResynchronizationRequest %x %x", p.SenderSSRC, p.MediaSSRC)
 }
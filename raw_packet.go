@@ -39,6 +39,11 @@ func (r *RawPacket) DestinationSSRC() []uint32 {
 	return []uint32{}
 }
 
+// Kind returns KindRaw.
+func (r *RawPacket) Kind() PacketKind {
+	return KindRaw
+}
+
 func (r RawPacket) String() string {
 	out := fmt.Sprintf("RawPacket: %v", ([]byte)(r))
 	return out
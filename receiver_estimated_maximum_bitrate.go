@@ -49,7 +49,6 @@ func (p ReceiverEstimatedMaximumBitrate) MarshalSize() int {
 
 // MarshalTo serializes the packet to the given byte slice.
 func (p ReceiverEstimatedMaximumBitrate) MarshalTo(buf []byte) (n int, err error) {
-	const bitratemax = 0x3FFFFp+63
 	/*
 	    0                   1                   2                   3
 	    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
@@ -93,32 +92,15 @@ func (p ReceiverEstimatedMaximumBitrate) MarshalTo(buf []byte) (n int, err error
 	// Write the length of the ssrcs to follow at the end
 	buf[16] = byte(len(p.SSRCs))
 
-	exp := 0
-	bitrate := p.Bitrate
-
-	if bitrate >= bitratemax {
-		bitrate = bitratemax
-	}
-
-	if bitrate < 0 {
-		return 0, errInvalidBitrate
-	}
-
-	for bitrate >= (1 << 18) {
-		bitrate /= 2.0
-		exp++
-	}
-
-	if exp >= (1 << 6) {
-		return 0, errInvalidBitrate
+	exp, mantissa, err := EncodeREMBBitrate(p.Bitrate)
+	if err != nil {
+		return 0, err
 	}
 
-	mantissa := uint(math.Floor(float64(bitrate)))
-
 	// We can't quite use the binary package because
 	// a) it's a uint24 and b) the exponent is only 6-bits
 	// Just trust me; this is big-endian encoding.
-	buf[17] = byte(exp<<2) | byte(mantissa>>16)
+	buf[17] = exp<<2 | byte(mantissa>>16)
 	buf[18] = byte(mantissa >> 8)
 	buf[19] = byte(mantissa)
 
@@ -134,7 +116,6 @@ func (p ReceiverEstimatedMaximumBitrate) MarshalTo(buf []byte) (n int, err error
 
 // Unmarshal reads a REMB packet from the given byte slice.
 func (p *ReceiverEstimatedMaximumBitrate) Unmarshal(buf []byte) (err error) {
-	const mantissamax = 0x7FFFFF
 	/*
 	    0                   1                   2                   3
 	    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
@@ -218,24 +199,11 @@ func (p *ReceiverEstimatedMaximumBitrate) Unmarshal(buf []byte) (err error) {
 		return errSSRCNumAndLengthMismatch
 	}
 
-	// Get the 6-bit exponent value.
+	// The 6-bit exponent is followed by the remaining 2-bits plus the next
+	// 16-bits of the mantissa.
 	exp := buf[17] >> 2
-	exp += 127 // bias for IEEE754
-	exp += 23  // IEEE754 biases the decimal to the left, abs-send-time biases it to the right
-
-	// The remaining 2-bits plus the next 16-bits are the mantissa.
 	mantissa := uint32(buf[17]&3)<<16 | uint32(buf[18])<<8 | uint32(buf[19])
-
-	if mantissa != 0 {
-		// ieee754 requires an implicit leading bit
-		for (mantissa & (mantissamax + 1)) == 0 {
-			exp--
-			mantissa *= 2
-		}
-	}
-
-	// bitrate = mantissa * 2^exp
-	p.Bitrate = math.Float32frombits((uint32(exp) << 23) | (mantissa & mantissamax))
+	p.Bitrate = DecodeREMBBitrate(exp, mantissa)
 
 	// Clear any existing SSRCs
 	p.SSRCs = nil
@@ -250,6 +218,60 @@ func (p *ReceiverEstimatedMaximumBitrate) Unmarshal(buf []byte) (err error) {
 	return nil
 }
 
+// EncodeREMBBitrate converts a bitrate in bits per second to the 6-bit
+// exponent and 18-bit mantissa of the REMB wire format, such that
+// bitrate = mantissa * 2^exp. The mantissa is rounded down, so the encoded
+// bitrate never exceeds the given one; the loss in precision is less than
+// 2^exp bits per second. Bitrates above the largest representable value,
+// 0x3FFFF * 2^63, are clamped to it. Negative bitrates are an error.
+func EncodeREMBBitrate(bitrate float32) (exp uint8, mantissa uint32, err error) {
+	const bitratemax = 0x3FFFFp+63
+
+	if bitrate >= bitratemax {
+		bitrate = bitratemax
+	}
+
+	if bitrate < 0 {
+		return 0, 0, errInvalidBitrate
+	}
+
+	for bitrate >= (1 << 18) {
+		bitrate /= 2.0
+		exp++
+	}
+
+	if exp >= (1 << 6) {
+		return 0, 0, errInvalidBitrate
+	}
+
+	return exp, uint32(math.Floor(float64(bitrate))), nil
+}
+
+// DecodeREMBBitrate converts the 6-bit exponent and 18-bit mantissa of the
+// REMB wire format to a bitrate in bits per second. Every non-zero mantissa
+// yields an exactly representable float32. Bits beyond the field widths are
+// ignored.
+func DecodeREMBBitrate(exp uint8, mantissa uint32) float32 {
+	const mantissamax = 0x7FFFFF
+
+	exp &= 0x3F
+	mantissa &= 0x3FFFF
+
+	exp += 127 // bias for IEEE754
+	exp += 23  // IEEE754 biases the decimal to the left, abs-send-time biases it to the right
+
+	if mantissa != 0 {
+		// ieee754 requires an implicit leading bit
+		for (mantissa & (mantissamax + 1)) == 0 {
+			exp--
+			mantissa *= 2
+		}
+	}
+
+	// bitrate = mantissa * 2^exp
+	return math.Float32frombits((uint32(exp) << 23) | (mantissa & mantissamax))
+}
+
 // Header returns the Header associated with this packet.
 func (p *ReceiverEstimatedMaximumBitrate) Header() Header {
 	return Header{
@@ -283,3 +305,8 @@ func (p *ReceiverEstimatedMaximumBitrate) String() string {
 func (p *ReceiverEstimatedMaximumBitrate) DestinationSSRC() []uint32 {
 	return p.SSRCs
 }
+
+// Kind returns KindReceiverEstimatedMaximumBitrate.
+func (p *ReceiverEstimatedMaximumBitrate) Kind() PacketKind {
+	return KindReceiverEstimatedMaximumBitrate
+}
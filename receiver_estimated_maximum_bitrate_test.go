@@ -128,3 +128,33 @@ func TestReceiverEstimatedMaximumBitrateOverflow(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(math.Float32frombits(0x62800000), packet.Bitrate)
 }
+
+func TestREMBBitrateEncoding(t *testing.T) {
+	for _, test := range []struct {
+		Bitrate  float32
+		Exp      uint8
+		Mantissa uint32
+		Decoded  float32
+	}{
+		{Bitrate: 0, Exp: 0, Mantissa: 0},
+		{Bitrate: 1, Exp: 0, Mantissa: 1, Decoded: 1},
+		{Bitrate: 1<<18 - 1, Exp: 0, Mantissa: 0x3FFFF, Decoded: 1<<18 - 1},
+		{Bitrate: 1 << 18, Exp: 1, Mantissa: 1 << 17, Decoded: 1 << 18},
+		{Bitrate: 1<<19 - 1, Exp: 1, Mantissa: 0x3FFFF, Decoded: 1<<19 - 2},
+		{Bitrate: 1 << 19, Exp: 2, Mantissa: 1 << 17, Decoded: 1 << 19},
+		{Bitrate: 8927168, Exp: 6, Mantissa: 139487, Decoded: 8927168},
+		{Bitrate: 0x3FFFFp+63, Exp: 63, Mantissa: 0x3FFFF, Decoded: 0x3FFFFp+63},
+		{Bitrate: math.MaxFloat32, Exp: 63, Mantissa: 0x3FFFF, Decoded: 0x3FFFFp+63},
+	} {
+		exp, mantissa, err := EncodeREMBBitrate(test.Bitrate)
+		assert.NoError(t, err)
+		assert.Equal(t, test.Exp, exp, "exponent of %v", test.Bitrate)
+		assert.Equal(t, test.Mantissa, mantissa, "mantissa of %v", test.Bitrate)
+		if test.Mantissa != 0 {
+			assert.Equal(t, test.Decoded, DecodeREMBBitrate(exp, mantissa), "decoded %v", test.Bitrate)
+		}
+	}
+
+	_, _, err := EncodeREMBBitrate(-1)
+	assert.ErrorIs(t, err, errInvalidBitrate)
+}
@@ -184,6 +184,11 @@ func (r *ReceiverReport) DestinationSSRC() []uint32 {
 	return out
 }
 
+// Kind returns KindReceiverReport.
+func (r *ReceiverReport) Kind() PacketKind {
+	return KindReceiverReport
+}
+
 func (r ReceiverReport) String() string {
 	out := fmt.Sprintf("ReceiverReport from %x\n", r.SSRC)
 	out += "\tSSRC    \tLost\tLastSequence\n"
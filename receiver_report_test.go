@@ -232,6 +232,27 @@ func TestReceiverReportRoundTrip(t *testing.T) {
 				ProfileExtensions: []byte{},
 			},
 		},
+		{
+			Name: "totallost max",
+			Report: ReceiverReport{
+				SSRC: 1,
+				Reports: []ReceptionReport{{
+					FractionLost: 0xFF,
+					TotalLost:    1<<24 - 1,
+				}},
+				ProfileExtensions: []byte{},
+			},
+		},
+		{
+			Name: "totallost just over max",
+			Report: ReceiverReport{
+				SSRC: 1,
+				Reports: []ReceptionReport{{
+					TotalLost: 1 << 24,
+				}},
+			},
+			WantError: errInvalidTotalLost,
+		},
 		{
 			Name: "totallost overflow",
 			Report: ReceiverReport{
@@ -40,7 +40,6 @@ type ReceptionReport struct {
 const (
 	receptionReportLength = 24
 	fractionLostOffset    = 4
-	totalLostOffset       = 5
 	lastSeqOffset         = 8
 	jitterOffset          = 12
 	lastSROffset          = 16
@@ -71,16 +70,16 @@ func (r ReceptionReport) Marshal() ([]byte, error) {
 
 	binary.BigEndian.PutUint32(rawPacket, r.SSRC)
 
-	rawPacket[fractionLostOffset] = r.FractionLost
-
-	// pack TotalLost into 24 bits
-	if r.TotalLost >= (1 << 25) {
+	// pack FractionLost into the first 8 bits and TotalLost into 24 bits
+	lost, err := setNBitsOfUint32(0, 8, 0, uint32(r.FractionLost))
+	if err != nil {
+		return nil, err
+	}
+	lost, err = setNBitsOfUint32(lost, 24, 8, r.TotalLost)
+	if err != nil {
 		return nil, errInvalidTotalLost
 	}
-	tlBytes := rawPacket[totalLostOffset:]
-	tlBytes[0] = byte(r.TotalLost >> 16)
-	tlBytes[1] = byte(r.TotalLost >> 8)
-	tlBytes[2] = byte(r.TotalLost)
+	binary.BigEndian.PutUint32(rawPacket[fractionLostOffset:], lost)
 
 	binary.BigEndian.PutUint32(rawPacket[lastSeqOffset:], r.LastSequenceNumber)
 	binary.BigEndian.PutUint32(rawPacket[jitterOffset:], r.Jitter)
@@ -117,8 +116,11 @@ func (r *ReceptionReport) Unmarshal(rawPacket []byte) error {
 	r.SSRC = binary.BigEndian.Uint32(rawPacket)
 	r.FractionLost = rawPacket[fractionLostOffset]
 
-	tlBytes := rawPacket[totalLostOffset:]
-	r.TotalLost = uint32(tlBytes[2]) | uint32(tlBytes[1])<<8 | uint32(tlBytes[0])<<16
+	totalLost, err := getNBitsFromUint32(binary.BigEndian.Uint32(rawPacket[fractionLostOffset:]), 24, 8)
+	if err != nil {
+		return err
+	}
+	r.TotalLost = totalLost
 
 	r.LastSequenceNumber = binary.BigEndian.Uint32(rawPacket[lastSeqOffset:])
 	r.Jitter = binary.BigEndian.Uint32(rawPacket[jitterOffset:])
@@ -5,9 +5,14 @@ package rtcp
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"sort"
+	"strings"
+	"time"
 )
 
 // https://www.rfc-editor.org/rfc/rfc8888.html#name-rtcp-congestion-control-fee
@@ -40,9 +45,29 @@ import (
 // +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
 
 var (
-	errReportBlockLength   = errors.New("feedback report blocks must be at least 8 bytes")
-	errIncorrectNumReports = errors.New("feedback report block contains less reports than num_reports")
-	errMetricBlockLength   = errors.New("feedback report metric blocks must be exactly 2 bytes")
+	errReportBlockLength    = errors.New("feedback report blocks must be at least 8 bytes")
+	errIncorrectNumReports  = errors.New("feedback report block contains less reports than num_reports")
+	errMetricBlockLength    = errors.New("feedback report metric blocks must be exactly 2 bytes")
+	errArrivalTimeOffset    = errors.New("arrival time offset out of range")
+	errReportBlockOrder     = errors.New("feedback report blocks for the same SSRC must be in sequence order")
+	errReportBlockOverlap   = errors.New("feedback report blocks for the same SSRC must not overlap")
+	errInvalidECN           = errors.New("invalid ECN value")
+	errRebaseOutOfRange     = errors.New("report timestamp shift exceeds arrival time offset range")
+	errSequenceWrap         = errors.New("feedback report block must not wrap around the sequence number space")
+	errSingleMetricBlock    = errors.New("feedback report block can not encode exactly one metric block")
+	errWriterClosed         = errors.New("feedback writer is closed")
+	errTooManyReportBlocks  = errors.New("feedback report contains too many report blocks")
+	errMultipleMediaSSRCs   = errors.New("transport-wide feedback can only describe a single media SSRC")
+	errNoMetricBlocks       = errors.New("feedback report contains no metric blocks")
+	errMissingArrivalTime   = errors.New("received packet has no arrival time")
+	errLostBeforeBegin      = errors.New("lost sequence number is before the begin sequence number")
+	errNonZeroPadding       = errors.New("feedback report block padding must be zero")
+	errBlockLengthMismatch  = errors.New("feedback report block length does not match num_reports")
+	errBitmapLengthMismatch = errors.New("received, ECN and offset slices must have the same length")
+	errUnknownMediaSSRC     = errors.New("feedback reports on a media SSRC that was not sent")
+	errSequenceNotSent      = errors.New("feedback reports on a sequence number that was not sent")
+	errReportMisaligned     = errors.New("internal error: feedback report is not 32-bit aligned")
+	errTooManyMetricBlocks  = errors.New("feedback covers more than the 65536 sequence numbers")
 )
 
 // ECN represents the two ECN bits
@@ -54,17 +79,69 @@ const (
 	ECNNonECT ECN = iota // 00
 
 	//nolint:misspell
-	// ECNECT1 signals ECN Capable Transport, ECT(0)
+	// ECNECT1 signals ECN Capable Transport, ECT(1)
 	ECNECT1 // 01
 
 	//nolint:misspell
-	// ECNECT0 signals ECN Capable Transport, ECT(1)
+	// ECNECT0 signals ECN Capable Transport, ECT(0)
 	ECNECT0 // 10
 
 	// ECNCE signals ECN Congestion Encountered, CE
 	ECNCE // 11
 )
 
+// ParseECN parses an ECN codepoint from its RFC 3168 name ("Non-ECT",
+// "ECT(0)", "ECT(1)" or "CE", case-insensitive) or from its two bit value
+// ("00", "01", "10" or "11").
+func ParseECN(s string) (ECN, error) {
+	for _, e := range []ECN{ECNNonECT, ECNECT1, ECNECT0, ECNCE} {
+		if strings.EqualFold(s, e.String()) || s == fmt.Sprintf("%02b", uint8(e)) {
+			return e, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", errInvalidECN, s)
+}
+
+// Valid reports whether e is one of the four ECN codepoints.
+func (e ECN) Valid() bool {
+	return e <= ECNCE
+}
+
+// The ECN field of a metric block echoes the two ECN bits of the IP header
+// unchanged: RFC 8888, Section 3.1 reports the ECN marking of the packet as
+// defined by RFC 3168, Section 5, which assigns 01 to ECT(1) and 10 to
+// ECT(0). The ECN constants use the same values, so the conversions below do
+// not reorder bits; they only spell out that no reordering is needed.
+
+// ToIPECN returns the IP header ECN field for e, the two least significant
+// bits of the IPv4 TOS or IPv6 Traffic Class octet. Bits of an invalid e
+// beyond the two ECN bits are dropped.
+func (e ECN) ToIPECN() uint8 {
+	return uint8(e) & 0x03
+}
+
+// FromIPECN returns the ECN codepoint of the IP header ECN field v. Only the
+// two least significant bits of v are used, so the whole TOS or Traffic
+// Class octet may be passed.
+func FromIPECN(v uint8) ECN {
+	return ECN(v & 0x03)
+}
+
+func (e ECN) String() string {
+	switch e {
+	case ECNNonECT:
+		return "Non-ECT"
+	case ECNECT1:
+		return "ECT(1)"
+	case ECNECT0:
+		return "ECT(0)"
+	case ECNCE:
+		return "CE"
+	default:
+		return fmt.Sprintf("ECN(%d)", uint8(e))
+	}
+}
+
 const (
 	reportTimestampLength = 4
 	reportBlockOffset     = 8
@@ -83,6 +160,17 @@ type CCFeedbackReport struct {
 	ReportTimestamp uint32
 }
 
+// NewCCFeedbackReport creates a CCFeedbackReport from senderSSRC with the
+// given report timestamp and report blocks. The header is always derived
+// from the report, so it is marshaled with FMT=11 and PT=205.
+func NewCCFeedbackReport(senderSSRC, reportTimestamp uint32, blocks []CCFeedbackReportBlock) *CCFeedbackReport {
+	return &CCFeedbackReport{
+		SenderSSRC:      senderSSRC,
+		ReportBlocks:    blocks,
+		ReportTimestamp: reportTimestamp,
+	}
+}
+
 // DestinationSSRC returns an array of SSRC values that this packet refers to.
 func (b CCFeedbackReport) DestinationSSRC() []uint32 {
 	ssrcs := make([]uint32, len(b.ReportBlocks))
@@ -92,6 +180,11 @@ func (b CCFeedbackReport) DestinationSSRC() []uint32 {
 	return ssrcs
 }
 
+// Kind returns KindCCFeedback.
+func (b CCFeedbackReport) Kind() PacketKind {
+	return KindCCFeedback
+}
+
 // Len returns the length of the report in bytes
 func (b *CCFeedbackReport) Len() int {
 	return b.MarshalSize()
@@ -118,27 +211,102 @@ func (b *CCFeedbackReport) Header() Header {
 
 // Marshal encodes the Congestion Control Feedback Report in binary
 func (b CCFeedbackReport) Marshal() ([]byte, error) {
-	header := b.Header()
-	headerBuf, err := header.Marshal()
+	buf := make([]byte, b.MarshalSize())
+	n, err := b.MarshalTo(buf)
 	if err != nil {
 		return nil, err
 	}
-	length := 4 * (header.Length + 1)
-	buf := make([]byte, length)
+	if n != len(buf) {
+		return nil, errWrongMarshalSize
+	}
+	return buf, nil
+}
+
+// MarshalTo encodes the Congestion Control Feedback Report into buf and
+// returns the number of bytes written. errPacketTooLarge is returned if the
+// report has more than 65536 32-bit words, which the header length can not
+// describe.
+func (b CCFeedbackReport) MarshalTo(buf []byte) (int, error) {
+	return marshalReportTo(buf, b.SenderSSRC, b.ReportTimestamp, len(b.ReportBlocks), func(i int) reportBlockMarshaler {
+		return &b.ReportBlocks[i]
+	})
+}
+
+// marshalReportTo encodes a report whose numBlocks report blocks are
+// returned by block. The report is sized from the len of the blocks. Report
+// blocks are padded to 32-bit words by construction, so the report never
+// needs the padding bit; a size that is not word aligned means the length
+// computation of a report block is broken and the header would describe the
+// wrong length, which is reported as errReportMisaligned.
+func marshalReportTo(buf []byte, senderSSRC, reportTimestamp uint32, numBlocks int, block func(i int) reportBlockMarshaler) (int, error) {
+	length := reportBlockOffset + reportTimestampLength
+	for i := 0; i < numBlocks; i++ {
+		length += block(i).len()
+	}
+	if length > maxPacketLength {
+		return 0, errPacketTooLarge
+	}
+	if length%4 != 0 {
+		return 0, fmt.Errorf("%w: %d bytes", errReportMisaligned, length)
+	}
+	header := Header{
+		Padding: false,
+		Count:   FormatCCFB,
+		Type:    TypeTransportSpecificFeedback,
+		Length:  uint16(length/4 - 1),
+	}
+	headerBuf, err := header.Marshal()
+	if err != nil {
+		return 0, err
+	}
+	if len(buf) < length {
+		return 0, errPacketTooShort
+	}
 	copy(buf[:headerLength], headerBuf)
-	binary.BigEndian.PutUint32(buf[headerLength:], b.SenderSSRC)
+	binary.BigEndian.PutUint32(buf[headerLength:], senderSSRC)
 	offset := reportBlockOffset
-	for _, block := range b.ReportBlocks {
-		b, err := block.marshal()
+	for i := 0; i < numBlocks; i++ {
+		n, err := marshalReportBlockTo(buf[offset:], block(i))
 		if err != nil {
-			return nil, err
+			return 0, fmt.Errorf("report block %d: %w", i, err)
 		}
-		copy(buf[offset:], b)
-		offset += block.len()
+		offset += n
 	}
 
-	binary.BigEndian.PutUint32(buf[offset:], b.ReportTimestamp)
-	return buf, nil
+	binary.BigEndian.PutUint32(buf[offset:], reportTimestamp)
+	return length, nil
+}
+
+// reportBlockMarshaler is the part of CCFeedbackReportBlock that
+// marshalReportTo relies on.
+type reportBlockMarshaler interface {
+	marshal() ([]byte, error)
+	len() int
+}
+
+// marshalReportBlockTo copies the encoding of block into buf. The buffer has
+// been sized from len, so an encoding of any other size means the two have
+// diverged and would corrupt the packet.
+func marshalReportBlockTo(buf []byte, block reportBlockMarshaler) (int, error) {
+	data, err := block.marshal()
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != block.len() || len(data) > len(buf) {
+		return 0, errWrongMarshalSize
+	}
+	return copy(buf, data), nil
+}
+
+// WriteTo marshals the Congestion Control Feedback Report and writes it to w,
+// implementing io.WriterTo
+func (b CCFeedbackReport) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, b.MarshalSize())
+	if _, err := b.MarshalTo(buf); err != nil {
+		return 0, err
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
 }
 
 func (b CCFeedbackReport) String() string {
@@ -153,48 +321,734 @@ func (b CCFeedbackReport) String() string {
 	return out
 }
 
+// Summary returns a one-line description of the report for compact logging,
+// such as "CCFB sender=0x00000001 ts=0x00010000 streams=2 reported=340
+// lost=12 ce=5". streams counts the distinct media SSRCs, reported the metric
+// blocks across all report blocks, and ce the received packets marked CE.
+func (b *CCFeedbackReport) Summary() string {
+	var streams, reported, lost, ce int
+	for i, block := range b.ReportBlocks {
+		streams++
+		for _, prev := range b.ReportBlocks[:i] {
+			if prev.MediaSSRC == block.MediaSSRC {
+				streams--
+				break
+			}
+		}
+		reported += len(block.MetricBlocks)
+		for _, mb := range block.MetricBlocks {
+			switch {
+			case !mb.Received:
+				lost++
+			case mb.ECN == ECNCE:
+				ce++
+			}
+		}
+	}
+	return fmt.Sprintf("CCFB sender=0x%08x ts=0x%08x streams=%d reported=%d lost=%d ce=%d",
+		b.SenderSSRC, b.ReportTimestamp, streams, reported, lost, ce)
+}
+
+// DecodeOptions controls how a CCFeedbackReport is decoded by
+// UnmarshalWithOptions. The zero value matches Unmarshal.
+type DecodeOptions struct {
+	// PreserveNotReceivedBits keeps the raw ECN and arrival time offset bits
+	// of metric blocks whose received bit is clear. By default they are
+	// canonicalized to ECNNonECT and 0, as RFC 8888 requires receivers to
+	// ignore them. Preserving them lets a decoded report marshal back to the
+	// exact input bytes, which is useful for conformance testing. Helpers that
+	// inspect ECN only consider received metric blocks, so preserved bits
+	// never affect their results. Preserving the bits does not relax any
+	// other validation.
+	PreserveNotReceivedBits bool
+
+	// AllowLengthMismatch decodes packets whose header length does not
+	// match the size of the buffer, as sent by some buggy senders. The
+	// buffer is trusted for the position of the report timestamp and report
+	// blocks are decoded up to the shorter of the two lengths, stopping at
+	// the first one that does not fit. The decoded report is kept and a
+	// *LengthMismatchError is returned. By default a buffer shorter than the
+	// header length is rejected and bytes beyond it are ignored.
+	AllowLengthMismatch bool
+
+	// RejectNonZeroPadding fails decoding with errNonZeroPadding if the two
+	// octets that align a report block with an odd number of metric blocks
+	// are not zero. By default they are ignored, as they carry no feedback.
+	RejectNonZeroPadding bool
+
+	// MaxReportBlocks limits the number of report blocks decoded from a
+	// single report, failing larger ones with errTooManyReportBlocks before
+	// memory for their blocks is allocated. 0 allows as many as the largest
+	// report can hold. Applications that know their peers send smaller
+	// reports can lower it.
+	MaxReportBlocks int
+
+	// MaxPacketSize limits the size in bytes of a report, including padding,
+	// that is decoded, failing larger ones with errPacketTooLarge. 0 allows
+	// the largest size the header length can describe.
+	MaxPacketSize int
+}
+
+// LengthMismatchError is returned by UnmarshalWithOptions with
+// AllowLengthMismatch set when the header length does not match the size of
+// the buffer. It only warns: the report has been decoded as far as possible.
+type LengthMismatchError struct {
+	// HeaderLength is the packet size in bytes given by the header
+	HeaderLength int
+
+	// PacketLength is the size of the buffer in bytes
+	PacketLength int
+}
+
+func (e *LengthMismatchError) Error() string {
+	return fmt.Sprintf("%v: header has %d bytes, buffer has %d", errLengthMismatch, e.HeaderLength, e.PacketLength)
+}
+
+// Unwrap returns the error the strict decoding would have returned
+func (e *LengthMismatchError) Unwrap() error {
+	return errLengthMismatch
+}
+
 // Unmarshal decodes the Congestion Control Feedback Report from binary
 func (b *CCFeedbackReport) Unmarshal(rawPacket []byte) error {
+	return b.UnmarshalWithOptions(rawPacket, DecodeOptions{})
+}
+
+// UnmarshalWithOptions decodes the Congestion Control Feedback Report from
+// binary, using opts to control decoding
+func (b *CCFeedbackReport) UnmarshalWithOptions(rawPacket []byte, opts DecodeOptions) error {
+	_, err := b.UnmarshalPrefix(rawPacket, opts)
+	return err
+}
+
+// UnmarshalFrom decodes the Congestion Control Feedback Report at the start
+// of rawPacket and returns the number of bytes it occupies according to its
+// header length, so that rawPacket[consumed:] holds the next packet. It is
+// UnmarshalPrefix with the default DecodeOptions.
+func (b *CCFeedbackReport) UnmarshalFrom(rawPacket []byte) (consumed int, err error) {
+	return b.UnmarshalPrefix(rawPacket, DecodeOptions{})
+}
+
+// UnmarshalPrefix decodes the Congestion Control Feedback Report at the start
+// of rawPacket and returns the number of bytes it occupies. The extent of the
+// report is taken from the header length and any bytes following it are
+// ignored, so callers need not slice the buffer exactly. With
+// AllowLengthMismatch set, the whole buffer is consumed instead.
+func (b *CCFeedbackReport) UnmarshalPrefix(rawPacket []byte, opts DecodeOptions) (int, error) {
 	if len(rawPacket) < headerLength+ssrcLength+reportTimestampLength {
-		return errPacketTooShort
+		return 0, errPacketTooShort
 	}
 
 	var h Header
 	if err := h.Unmarshal(rawPacket); err != nil {
-		return err
+		return 0, err
+	}
+	if h.Type != TypeTransportSpecificFeedback || h.Count != FormatCCFB {
+		return 0, errWrongType
+	}
+	// Bytes beyond the header length belong to whatever follows the report.
+	if packetLength := (int(h.Length) + 1) * 4; !opts.AllowLengthMismatch && len(rawPacket) > packetLength {
+		rawPacket = rawPacket[:packetLength]
+		if len(rawPacket) < headerLength+ssrcLength+reportTimestampLength {
+			return 0, errLengthMismatch
+		}
 	}
-	if h.Type != TypeTransportSpecificFeedback {
-		return errWrongType
+	maxPacketSize := opts.MaxPacketSize
+	if maxPacketSize == 0 {
+		maxPacketSize = maxPacketLength
+	}
+	if len(rawPacket) > maxPacketSize {
+		return 0, errPacketTooLarge
+	}
+	maxReportBlocks := opts.MaxReportBlocks
+	if maxReportBlocks == 0 {
+		maxReportBlocks = maxReportBlocksPerReport
+	}
+
+	var mismatch *LengthMismatchError
+	if err := h.Validate(len(rawPacket)); err != nil {
+		if !opts.AllowLengthMismatch || !errors.Is(err, errLengthMismatch) {
+			return 0, err
+		}
+		mismatch = &LengthMismatchError{
+			HeaderLength: (int(h.Length) + 1) * 4,
+			PacketLength: len(rawPacket),
+		}
+	}
+
+	// The report timestamp is the last word of the body, which ends before
+	// any padding octets. The last octet holds the padding count.
+	bodyLength := len(rawPacket)
+	if h.Padding {
+		paddingLength := int(rawPacket[bodyLength-1])
+		if paddingLength == 0 || bodyLength-paddingLength < headerLength+ssrcLength+reportTimestampLength {
+			return 0, errLengthMismatch
+		}
+		bodyLength -= paddingLength
 	}
 
 	b.SenderSSRC = binary.BigEndian.Uint32(rawPacket[headerLength:])
 
-	reportTimestampOffset := len(rawPacket) - reportTimestampLength
+	reportTimestampOffset := bodyLength - reportTimestampLength
 	b.ReportTimestamp = binary.BigEndian.Uint32(rawPacket[reportTimestampOffset:])
 
+	blocksEnd := reportTimestampOffset
+	if mismatch != nil && mismatch.HeaderLength < blocksEnd {
+		blocksEnd = mismatch.HeaderLength
+	}
+
 	offset := reportBlockOffset
-	b.ReportBlocks = []CCFeedbackReportBlock{}
-	for offset < reportTimestampOffset {
+	// Reuse the existing backing arrays, if any, to avoid allocating on
+	// every call when the same report is decoded into repeatedly.
+	if b.ReportBlocks == nil {
+		b.ReportBlocks = []CCFeedbackReportBlock{}
+	} else {
+		b.ReportBlocks = b.ReportBlocks[:0]
+	}
+	for offset < blocksEnd {
+		if len(b.ReportBlocks) >= maxReportBlocks {
+			return 0, errTooManyReportBlocks
+		}
 		var block CCFeedbackReportBlock
-		if err := block.unmarshal(rawPacket[offset:]); err != nil {
-			return err
+		if n := len(b.ReportBlocks); n < cap(b.ReportBlocks) {
+			block = b.ReportBlocks[:n+1][n]
+		}
+		if err := block.unmarshalWithOptions(rawPacket[offset:blocksEnd], opts); err != nil {
+			if mismatch != nil {
+				break
+			}
+			return 0, fmt.Errorf("report block %d at offset %d: %w", len(b.ReportBlocks), offset, err)
+		}
+		// The metric blocks fit, but the padding of an odd number of them
+		// would overlap the report timestamp.
+		if offset+block.len() > blocksEnd && mismatch == nil {
+			return 0, fmt.Errorf("report block %d at offset %d: %w", len(b.ReportBlocks), offset, errBlockLengthMismatch)
 		}
 		b.ReportBlocks = append(b.ReportBlocks, block)
 		offset += block.len()
 	}
 
+	if mismatch != nil {
+		return len(rawPacket), mismatch
+	}
+	return len(rawPacket), nil
+}
+
+// Reset clears the report for reuse, zeroing SenderSSRC and ReportTimestamp
+// and truncating ReportBlocks to length 0. Reset does not free the backing
+// arrays: the report blocks and their metric blocks keep their capacity, so
+// decoding a stream of reports into the same reset report with Unmarshal does
+// not allocate once it has grown to fit them.
+func (b *CCFeedbackReport) Reset() {
+	b.SenderSSRC = 0
+	b.ReportBlocks = b.ReportBlocks[:0]
+	b.ReportTimestamp = 0
+}
+
+// UnmarshalCCFeedbackReports decodes a buffer of concatenated Congestion
+// Control Feedback Reports, splitting it by the header length of each. Every
+// packet in the buffer must be a report; an empty buffer fails with
+// errPacketTooShort.
+func UnmarshalCCFeedbackReports(rawPacket []byte) ([]CCFeedbackReport, error) {
+	if len(rawPacket) == 0 {
+		return nil, errPacketTooShort
+	}
+
+	var reports []CCFeedbackReport
+	for offset := 0; offset < len(rawPacket); {
+		var report CCFeedbackReport
+		n, err := report.UnmarshalPrefix(rawPacket[offset:], DecodeOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("report %d at offset %d: %w", len(reports), offset, err)
+		}
+		reports = append(reports, report)
+		offset += n
+	}
+	return reports, nil
+}
+
+// ValidateRFC8888 checks the report against the rules of RFC 8888 that can
+// not be expressed by the wire format alone: every report block must carry
+// at most MaxMetricBlocksPerReport metric blocks whose arrival time offsets
+// fit into 13 bits, and report blocks for the same MediaSSRC must appear in
+// increasing sequence number order without overlapping.
+func (b *CCFeedbackReport) ValidateRFC8888() error {
+	last := make(map[uint32]CCFeedbackReportBlock, len(b.ReportBlocks))
+	for i, block := range b.ReportBlocks {
+		if len(block.MetricBlocks) > MaxMetricBlocksPerReport {
+			return fmt.Errorf("report block %d: %w", i, errTooManyReports)
+		}
+		for _, mb := range block.MetricBlocks {
+			if mb.ArrivalTimeOffset > maxArrivalTimeOffset {
+				return fmt.Errorf("report block %d: %w", i, errArrivalTimeOffset)
+			}
+		}
+
+		if len(block.MetricBlocks) == 0 {
+			continue
+		}
+		if prev, ok := last[block.MediaSSRC]; ok {
+			// Sequence numbers are compared using serial number arithmetic so
+			// that blocks continuing past a wraparound are still in order.
+			prevBegin, prevEnd, _ := prev.SequenceRange()
+			begin, _, _ := block.SequenceRange()
+			switch {
+			case begin-prevBegin >= 1<<15:
+				return fmt.Errorf("report block %d: %w", i, errReportBlockOrder)
+			case begin-prevEnd >= 1<<15:
+				return fmt.Errorf("report block %d: %w", i, errReportBlockOverlap)
+			}
+		}
+		last[block.MediaSSRC] = block
+	}
+	return nil
+}
+
+// SortBlocks orders the report blocks by MediaSSRC and then by
+// BeginSequence, so that reports built from unordered input, such as a map,
+// marshal the same way every time. If a block of a media source continues
+// past a wraparound, into the block at sequence number 0 or within itself,
+// its blocks stay in serial number order instead: they start after the
+// largest gap between their BeginSequence values, so a block at 65530
+// reaching 65535 sorts before one at 0.
+func (b *CCFeedbackReport) SortBlocks() {
+	blocks := b.ReportBlocks
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].MediaSSRC != blocks[j].MediaSSRC {
+			return blocks[i].MediaSSRC < blocks[j].MediaSSRC
+		}
+		return blocks[i].BeginSequence < blocks[j].BeginSequence
+	})
+
+	for start := 0; start < len(blocks); {
+		end := start + 1
+		for end < len(blocks) && blocks[end].MediaSSRC == blocks[start].MediaSSRC {
+			end++
+		}
+		group := blocks[start:end]
+		start = end
+
+		wraps := false
+		for _, block := range group {
+			blockEnd := int(block.BeginSequence) + len(block.MetricBlocks)
+			if blockEnd > math.MaxUint16+1 || blockEnd == math.MaxUint16+1 && group[0].BeginSequence == 0 {
+				wraps = true
+				break
+			}
+		}
+		if !wraps {
+			continue
+		}
+
+		// The gap before the first block wraps around from the last one.
+		first, largest := 0, group[0].BeginSequence-group[len(group)-1].BeginSequence
+		for i := 1; i < len(group); i++ {
+			if gap := group[i].BeginSequence - group[i-1].BeginSequence; gap > largest {
+				first, largest = i, gap
+			}
+		}
+		if first != 0 {
+			rotated := append(append([]CCFeedbackReportBlock{}, group[first:]...), group[:first]...)
+			copy(group, rotated)
+		}
+	}
+}
+
+// ValidateAgainstSent checks the report against what the sender has sent,
+// given the highest sequence number sent on every media source. Feedback on
+// a media source missing from highestSent, or on sequence numbers after the
+// highest one sent, can not describe real packets and is rejected, as it
+// may come from a broken or malicious receiver. Sequence numbers are
+// compared using serial number arithmetic, so a report block may continue
+// past a wraparound up to a highest sent sequence number just after it.
+func (b *CCFeedbackReport) ValidateAgainstSent(highestSent map[uint32]uint16) error {
+	for i, block := range b.ReportBlocks {
+		if len(block.MetricBlocks) == 0 {
+			continue
+		}
+		highest, ok := highestSent[block.MediaSSRC]
+		if !ok {
+			return fmt.Errorf("report block %d: %w: %d", i, errUnknownMediaSSRC, block.MediaSSRC)
+		}
+		end := block.BeginSequence + uint16(len(block.MetricBlocks)-1)
+		if highest-end >= 1<<15 {
+			return fmt.Errorf("report block %d: %w: %d after %d", i, errSequenceNotSent, end, highest)
+		}
+	}
+	return nil
+}
+
+// ECNCounts tallies the metric blocks of all report blocks: received packets
+// are counted by their ECN marking, packets that were not received are
+// counted as lost. The padding that aligns odd-sized report blocks is not a
+// metric block and is never counted.
+func (b *CCFeedbackReport) ECNCounts() (ect0, ect1, ce, nonECT, lost int) {
+	for _, block := range b.ReportBlocks {
+		for _, mb := range block.MetricBlocks {
+			if !mb.Received {
+				lost++
+				continue
+			}
+			switch mb.ECN {
+			case ECNECT0:
+				ect0++
+			case ECNECT1:
+				ect1++
+			case ECNCE:
+				ce++
+			default:
+				nonECT++
+			}
+		}
+	}
+	return
+}
+
+// CEMarkedCount returns the number of received packets marked CE, as needed
+// by L4S congestion control. Packets that were not received are never
+// counted, whatever their ECN bits.
+func (b *CCFeedbackReport) CEMarkedCount() int {
+	var ce int
+	for _, block := range b.ReportBlocks {
+		for _, mb := range block.MetricBlocks {
+			if mb.Received && mb.ECN == ECNCE {
+				ce++
+			}
+		}
+	}
+	return ce
+}
+
+// HasCEMarks reports whether any received packet is marked CE.
+func (b *CCFeedbackReport) HasCEMarks() bool {
+	for _, block := range b.ReportBlocks {
+		for _, mb := range block.MetricBlocks {
+			if mb.Received && mb.ECN == ECNCE {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FirstCESequence returns the sequence number of the earliest received packet
+// of ssrc marked CE, or false if there is none. Sequence numbers are compared
+// using serial number arithmetic, so a mark just after a wrap past 65535 is
+// later than one just before it.
+func (b *CCFeedbackReport) FirstCESequence(ssrc uint32) (uint16, bool) {
+	var first uint16
+	found := false
+	for _, block := range b.ReportBlocks {
+		if block.MediaSSRC != ssrc {
+			continue
+		}
+		for i, mb := range block.MetricBlocks {
+			if !mb.Received || mb.ECN != ECNCE {
+				continue
+			}
+			seq := block.BeginSequence + uint16(i)
+			if distance := first - seq; !found || (distance != 0 && distance < 1<<15) {
+				first = seq
+				found = true
+			}
+			// Later metric blocks of this block can not be earlier.
+			break
+		}
+	}
+	return first, found
+}
+
+// StreamFeedbackStats summarizes the feedback a report carries for one media
+// source.
+type StreamFeedbackStats struct {
+	// Received and Lost count the packets reported as received and not
+	// received.
+	Received int
+	Lost     int
+
+	// Received packets by ECN marking
+	NonECT int
+	ECT0   int
+	ECT1   int
+	CE     int
+}
+
+// StatsBySSRC aggregates the metric blocks of the report per MediaSSRC,
+// combining all report blocks for the same source, such as the two halves
+// of a range split at a sequence number wrap.
+func (b *CCFeedbackReport) StatsBySSRC() map[uint32]StreamFeedbackStats {
+	stats := make(map[uint32]StreamFeedbackStats)
+	for _, block := range b.ReportBlocks {
+		s := stats[block.MediaSSRC]
+		for _, mb := range block.MetricBlocks {
+			if !mb.Received {
+				s.Lost++
+				continue
+			}
+			s.Received++
+			switch mb.ECN {
+			case ECNECT0:
+				s.ECT0++
+			case ECNECT1:
+				s.ECT1++
+			case ECNCE:
+				s.CE++
+			default:
+				s.NonECT++
+			}
+		}
+		stats[block.MediaSSRC] = s
+	}
+	return stats
+}
+
+// LossRate returns the fraction of metric blocks across all report blocks
+// that report a packet as not received, or 0 if there are none.
+func (b *CCFeedbackReport) LossRate() float64 {
+	var lost, total int
+	for _, block := range b.ReportBlocks {
+		for _, mb := range block.MetricBlocks {
+			if !mb.Received {
+				lost++
+			}
+		}
+		total += len(block.MetricBlocks)
+	}
+	return lossRate(lost, total)
+}
+
+// LossRateBySSRC returns the loss rate of every MediaSSRC in the report, as
+// LossRate does for the whole report.
+func (b *CCFeedbackReport) LossRateBySSRC() map[uint32]float64 {
+	stats := b.StatsBySSRC()
+	rates := make(map[uint32]float64, len(stats))
+	for ssrc, s := range stats {
+		rates[ssrc] = lossRate(s.Lost, s.Received+s.Lost)
+	}
+	return rates
+}
+
+func lossRate(lost, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(lost) / float64(total)
+}
+
+// DiffFeedback compares the feedback two consecutive reports carry for ssrc
+// and returns the sequence numbers whose state changed: newlyLost were
+// received according to prev but are reported lost by curr, newlyReceived
+// were lost according to prev but are reported received by curr. Only
+// sequence numbers reported by both reports are compared; those outside the
+// overlap of their ranges are ignored. Sequence numbers are returned in the
+// order curr reports them.
+func DiffFeedback(prev, curr *CCFeedbackReport, ssrc uint32) (newlyLost []uint16, newlyReceived []uint16) {
+	received := make(map[uint16]bool)
+	for _, block := range prev.ReportBlocks {
+		if block.MediaSSRC != ssrc {
+			continue
+		}
+		for i, mb := range block.MetricBlocks {
+			received[block.BeginSequence+uint16(i)] = mb.Received
+		}
+	}
+
+	for _, block := range curr.ReportBlocks {
+		if block.MediaSSRC != ssrc {
+			continue
+		}
+		for i, mb := range block.MetricBlocks {
+			seq := block.BeginSequence + uint16(i)
+			wasReceived, ok := received[seq]
+			switch {
+			case !ok || wasReceived == mb.Received:
+			case mb.Received:
+				newlyReceived = append(newlyReceived, seq)
+			default:
+				newlyLost = append(newlyLost, seq)
+			}
+		}
+	}
+	return newlyLost, newlyReceived
+}
+
+// ArrivalDeltas returns the spacing between the arrivals of consecutive
+// received packets of ssrc, in the order the report lists them, as input to
+// a jitter estimate. Each arrival time is derived from the metric block's
+// ArrivalDelay relative to the report timestamp. Lost packets are skipped,
+// so a delta spans them. A received packet without an arrival time, such as
+// one marked 0x1FFF, breaks the sequence: no delta is computed across it. A
+// delta is negative if a packet arrived before the one preceding it in
+// sequence number order.
+func ArrivalDeltas(r *CCFeedbackReport, ssrc uint32) []time.Duration {
+	var deltas []time.Duration
+	var prev time.Duration
+	havePrev := false
+	for _, block := range r.ReportBlocks {
+		if block.MediaSSRC != ssrc {
+			continue
+		}
+		for _, mb := range block.MetricBlocks {
+			switch {
+			case !mb.Received:
+				continue
+			case !mb.HasArrivalTime():
+				havePrev = false
+				continue
+			}
+			delay := mb.ArrivalDelay()
+			if havePrev {
+				// Later arrivals are closer to the report timestamp.
+				deltas = append(deltas, prev-delay)
+			}
+			prev = delay
+			havePrev = true
+		}
+	}
+	return deltas
+}
+
+// TruncateToMTU drops the oldest feedback from the report until its
+// MarshalSize is at most mtu, and returns the number of report blocks that
+// were dropped entirely; metric blocks trimmed from blocks that are kept are
+// not counted. The media sources give up feedback in turn, so that every one
+// keeps its most recent sequence ranges: each turn trims the oldest report
+// block of a media source by four bytes, advancing its BeginSequence, or
+// drops it if fewer than two metric blocks would remain. The report
+// timestamp is kept. If mtu is smaller than a report without report blocks,
+// all of them are dropped.
+func (b *CCFeedbackReport) TruncateToMTU(mtu int) (dropped int) {
+	size := b.MarshalSize()
+	var ssrcs []uint32
+	seen := make(map[uint32]struct{})
+	for _, block := range b.ReportBlocks {
+		if _, ok := seen[block.MediaSSRC]; !ok {
+			seen[block.MediaSSRC] = struct{}{}
+			ssrcs = append(ssrcs, block.MediaSSRC)
+		}
+	}
+
+	for turn := 0; len(b.ReportBlocks) > 0 && size > mtu; turn++ {
+		ssrc := ssrcs[turn%len(ssrcs)]
+		oldest := -1
+		for i := range b.ReportBlocks {
+			if b.ReportBlocks[i].MediaSSRC == ssrc {
+				oldest = i
+				break
+			}
+		}
+		if oldest < 0 {
+			continue
+		}
+		block := &b.ReportBlocks[oldest]
+
+		// Metric blocks take two bytes each, in pairs to keep the block
+		// aligned to 32 bits, so removing one from an odd number or two from
+		// an even number saves four bytes.
+		removed := 2 - len(block.MetricBlocks)%2
+		if len(block.MetricBlocks)-removed >= 2 {
+			block.MetricBlocks = block.MetricBlocks[removed:]
+			block.BeginSequence += uint16(removed)
+			size -= 4
+			continue
+		}
+
+		size -= block.len()
+		b.ReportBlocks = append(b.ReportBlocks[:oldest], b.ReportBlocks[oldest+1:]...)
+		dropped++
+	}
+	return dropped
+}
+
+// Rebase moves the report onto newTimestamp while keeping the arrival times
+// it describes: the ArrivalTimeOffset of every received metric block is
+// shifted by the difference between the two timestamps. Offsets that no
+// longer fit the 13-bit range become unavailable (0x1FFF); over-range offsets
+// (0x1FFE) stay over-range unless the report moves back in time. The timestamps
+// are the middle 32 bits of an NTP timestamp, in units of 1/65536 seconds.
+//
+// If the shift is at least as large as the whole offset range, no offset
+// could remain valid; an error is returned and the report is left unchanged.
+func (b *CCFeedbackReport) Rebase(newTimestamp uint32) error {
+	// Serial arithmetic, so rebasing across a wrap of the timestamp works.
+	delta := int64(int32(newTimestamp - b.ReportTimestamp))
+	shift := int64(math.Round(float64(delta) * arrivalTimeOffsetsPerSecond / reportTimestampsPerSecond))
+	if shift <= -ArrivalTimeOffsetOverRange || shift >= ArrivalTimeOffsetOverRange {
+		return fmt.Errorf("%w: %d", errRebaseOutOfRange, shift)
+	}
+
+	for i := range b.ReportBlocks {
+		for j := range b.ReportBlocks[i].MetricBlocks {
+			mb := &b.ReportBlocks[i].MetricBlocks[j]
+			if !mb.Received || mb.ArrivalTimeOffset == ArrivalTimeOffsetUnavailable {
+				continue
+			}
+			if mb.ArrivalTimeOffset == ArrivalTimeOffsetOverRange && shift >= 0 {
+				continue
+			}
+			offset := int64(mb.ArrivalTimeOffset) + shift
+			if mb.ArrivalTimeOffset == ArrivalTimeOffsetOverRange || offset < 0 || offset >= ArrivalTimeOffsetOverRange {
+				mb.ArrivalTimeOffset = ArrivalTimeOffsetUnavailable
+				continue
+			}
+			mb.ArrivalTimeOffset = uint16(offset)
+		}
+	}
+	b.ReportTimestamp = newTimestamp
+
 	return nil
 }
 
+const (
+	minFeedbackInterval = 50 * time.Millisecond
+	maxFeedbackInterval = 200 * time.Millisecond
+)
+
+// SuggestedFeedbackInterval returns how often a receiver should send
+// congestion control feedback on numSenders media senders. RFC 8888 Section
+// 5 asks for feedback about once per round-trip time, so the sender can react
+// within an RTT. Every report carries a report block per sender, so like the
+// RTCP interval of RFC 3550 Section 6.2 the lower bound grows with the number
+// of senders, by 50ms each, to keep the feedback overhead in check. The upper
+// bound of 200ms keeps congestion controllers responsive:
+//
+//	interval = min(max(rtt, numSenders*50ms), 200ms)
+//
+// A numSenders below 1 counts as 1.
+func SuggestedFeedbackInterval(rtt time.Duration, numSenders int) time.Duration {
+	if numSenders < 1 {
+		numSenders = 1
+	}
+	if numSenders > int(maxFeedbackInterval/minFeedbackInterval) {
+		return maxFeedbackInterval
+	}
+	interval := minFeedbackInterval * time.Duration(numSenders)
+	if rtt > interval {
+		interval = rtt
+	}
+	if interval > maxFeedbackInterval {
+		return maxFeedbackInterval
+	}
+	return interval
+}
+
 const (
 	ssrcOffset          = 0
 	beginSequenceOffset = 4
 	numReportsOffset    = 6
 	reportsOffset       = 8
-
-	maxMetricBlocks = 16384
 )
 
+// MaxMetricBlocksPerReport is the maximum number of metric blocks a single
+// CCFeedbackReportBlock can carry. Feedback on more packets of one media
+// source has to be spread over several report blocks with consecutive
+// BeginSequence values, as built by NewCCFeedbackReportForStream.
+const MaxMetricBlocksPerReport = 16384
+
+// maxReportBlocksPerReport is the number of empty report blocks that fill
+// the largest report the header length can describe
+const maxReportBlocksPerReport = (maxPacketLength - reportBlockOffset - reportTimestampLength) / reportsOffset
+
 // CCFeedbackReportBlock is a Feedback Report Block
 type CCFeedbackReportBlock struct {
 	// SSRC of the RTP stream on which this block is reporting
@@ -203,6 +1057,36 @@ type CCFeedbackReportBlock struct {
 	MetricBlocks  []CCFeedbackMetricBlock
 }
 
+// NewReportBlockFromBitmap builds a report block on the packets of ssrc
+// starting at sequence number begin, where received[i] tells whether packet
+// begin+i arrived and ecn[i] and offsets[i] give its ECN marking and arrival
+// time offset in 1/1024 seconds. The ECN marking and offset of packets that
+// did not arrive are ignored. The slices must have the same length, at most
+// MaxMetricBlocksPerReport, and offsets must fit into 13 bits.
+func NewReportBlockFromBitmap(ssrc uint32, begin uint16, received []bool, ecn []ECN, offsets []uint16) (CCFeedbackReportBlock, error) {
+	if len(ecn) != len(received) || len(offsets) != len(received) {
+		return CCFeedbackReportBlock{}, errBitmapLengthMismatch
+	}
+	if len(received) > MaxMetricBlocksPerReport {
+		return CCFeedbackReportBlock{}, errTooManyReports
+	}
+
+	metricBlocks := make([]CCFeedbackMetricBlock, len(received))
+	for i, r := range received {
+		if !r {
+			continue
+		}
+		if !ecn[i].Valid() {
+			return CCFeedbackReportBlock{}, fmt.Errorf("sequence number %d: %w", begin+uint16(i), errInvalidECN)
+		}
+		if offsets[i] > maxArrivalTimeOffset {
+			return CCFeedbackReportBlock{}, fmt.Errorf("sequence number %d: %w", begin+uint16(i), errArrivalTimeOffset)
+		}
+		metricBlocks[i] = CCFeedbackMetricBlock{Received: true, ECN: ecn[i], ArrivalTimeOffset: offsets[i]}
+	}
+	return CCFeedbackReportBlock{MediaSSRC: ssrc, BeginSequence: begin, MetricBlocks: metricBlocks}, nil
+}
+
 // len returns the length of the report block in bytes
 func (b *CCFeedbackReportBlock) len() int {
 	n := len(b.MetricBlocks)
@@ -223,9 +1107,134 @@ func (b CCFeedbackReportBlock) String() string {
 	return out
 }
 
+// SplitByMaxReports splits the block into consecutive blocks of at most
+// MaxMetricBlocksPerReport metric blocks each that do not wrap past sequence
+// number 65535, adjusting BeginSequence so the resulting blocks cover the
+// same sequence numbers. Where a block would leave a single metric block,
+// which can not be encoded, for the next one, it is shortened by one. A
+// single metric block is only left if a wraparound isolates one packet, which
+// Validate reports. The returned blocks share the MetricBlocks backing array
+// with b.
+func (b CCFeedbackReportBlock) SplitByMaxReports() []CCFeedbackReportBlock {
+	if _, _, wrapped := b.SequenceRange(); !wrapped && len(b.MetricBlocks) <= MaxMetricBlocksPerReport {
+		return []CCFeedbackReportBlock{b}
+	}
+	return splitMetricBlocks(b.MediaSSRC, b.BeginSequence, b.MetricBlocks)
+}
+
+// splitMetricBlocks spreads metricBlocks, the feedback on consecutive packets
+// of mediaSSRC starting at sequence number begin, over report blocks: a new
+// one starts after MaxMetricBlocksPerReport metric blocks and at a sequence
+// number wraparound. A block is shortened by one rather than leave a single
+// metric block for the next one, so single metric blocks only remain if
+// metricBlocks holds one or a wraparound isolates one. The blocks share the
+// backing array of metricBlocks.
+func splitMetricBlocks(mediaSSRC uint32, begin uint16, metricBlocks []CCFeedbackMetricBlock) []CCFeedbackReportBlock {
+	blocks := []CCFeedbackReportBlock{}
+	for len(metricBlocks) > 0 {
+		segment := len(metricBlocks)
+		if untilWrap := math.MaxUint16 + 1 - int(begin); segment > untilWrap {
+			segment = untilWrap
+		}
+
+		size := segment
+		if size > MaxMetricBlocksPerReport {
+			size = MaxMetricBlocksPerReport
+			if segment-size == 1 {
+				size--
+			}
+		}
+		blocks = append(blocks, CCFeedbackReportBlock{
+			MediaSSRC:     mediaSSRC,
+			BeginSequence: begin,
+			MetricBlocks:  metricBlocks[:size:size],
+		})
+		metricBlocks = metricBlocks[size:]
+		begin += uint16(size)
+	}
+	return blocks
+}
+
+// Validate checks that the block can be encoded without losing feedback.
+// The wire format only stores BeginSequence and the number of metric
+// blocks, so MetricBlocks must describe consecutive sequence numbers; a gap
+// has to be expressed as metric blocks for lost packets rather than by
+// leaving entries out. Validate reports blocks with more than
+// MaxMetricBlocksPerReport metric blocks, blocks whose range would wrap
+// past sequence number 65535, which have to be split at the wrap, and
+// blocks with exactly one metric block, which num_reports as encoded here
+// can not tell apart from an empty block.
+func (b *CCFeedbackReportBlock) Validate() error {
+	switch n := len(b.MetricBlocks); {
+	case n == 0:
+		return nil
+	case n == 1:
+		return errSingleMetricBlock
+	case n > MaxMetricBlocksPerReport:
+		return errTooManyReports
+	default:
+		if _, _, wrapped := b.SequenceRange(); wrapped {
+			return errSequenceWrap
+		}
+		return nil
+	}
+}
+
+// SequenceRange returns the sequence numbers the block reports on, from
+// begin up to but excluding end. wrapped is true if the range continues past
+// 65535 to lower sequence numbers; a range ending at 65535 has an end of 0
+// but does not wrap.
+func (b *CCFeedbackReportBlock) SequenceRange() (begin, end uint16, wrapped bool) {
+	begin = b.BeginSequence
+	end = begin + uint16(len(b.MetricBlocks))
+	return begin, end, int(begin)+len(b.MetricBlocks) > math.MaxUint16+1
+}
+
+// AddArrival records mb as the feedback for sequence number seq. The first
+// arrival added to an empty block sets its BeginSequence. Later ones are
+// placed relative to BeginSequence, using serial number arithmetic: gaps are
+// filled with metric blocks for lost packets, an arrival before
+// BeginSequence moves BeginSequence back, and an arrival for a sequence
+// number already covered replaces its metric block. errTooManyReports is
+// returned if the block would exceed MaxMetricBlocksPerReport.
+func (b *CCFeedbackReportBlock) AddArrival(seq uint16, mb CCFeedbackMetricBlock) error {
+	if len(b.MetricBlocks) == 0 {
+		b.BeginSequence = seq
+		b.MetricBlocks = append(b.MetricBlocks[:0], mb)
+		return nil
+	}
+
+	if distance := seq - b.BeginSequence; distance < 1<<15 {
+		index := int(distance)
+		if index < len(b.MetricBlocks) {
+			b.MetricBlocks[index] = mb
+			return nil
+		}
+		if index >= MaxMetricBlocksPerReport {
+			return errTooManyReports
+		}
+		for len(b.MetricBlocks) < index {
+			b.MetricBlocks = append(b.MetricBlocks, CCFeedbackMetricBlock{})
+		}
+		b.MetricBlocks = append(b.MetricBlocks, mb)
+		return nil
+	}
+
+	shift := int(b.BeginSequence - seq)
+	if shift+len(b.MetricBlocks) > MaxMetricBlocksPerReport {
+		return errTooManyReports
+	}
+	metricBlocks := make([]CCFeedbackMetricBlock, shift+len(b.MetricBlocks))
+	metricBlocks[0] = mb
+	copy(metricBlocks[shift:], b.MetricBlocks)
+	b.MetricBlocks = metricBlocks
+	b.BeginSequence = seq
+	return nil
+}
+
 // marshal encodes the Congestion Control Feedback Report Block in binary
 func (b CCFeedbackReportBlock) marshal() ([]byte, error) {
-	if len(b.MetricBlocks) > maxMetricBlocks {
+	if len(b.MetricBlocks) > MaxMetricBlocksPerReport {
 		return nil, errTooManyReports
 	}
 
@@ -253,6 +1262,10 @@ func (b CCFeedbackReportBlock) marshal() ([]byte, error) {
 
 // Unmarshal decodes the Congestion Control Feedback Report Block from binary
 func (b *CCFeedbackReportBlock) unmarshal(rawPacket []byte) error {
+	return b.unmarshalWithOptions(rawPacket, DecodeOptions{})
+}
+
+func (b *CCFeedbackReportBlock) unmarshalWithOptions(rawPacket []byte, opts DecodeOptions) error {
 	if len(rawPacket) < reportsOffset {
 		return errReportBlockLength
 	}
@@ -260,6 +1273,9 @@ func (b *CCFeedbackReportBlock) unmarshal(rawPacket []byte) error {
 	b.BeginSequence = binary.BigEndian.Uint16(rawPacket[beginSequenceOffset:numReportsOffset])
 	numReportsField := binary.BigEndian.Uint16(rawPacket[numReportsOffset:])
 	if numReportsField == 0 {
+		if b.MetricBlocks != nil {
+			b.MetricBlocks = b.MetricBlocks[:0]
+		}
 		return nil
 	}
 
@@ -274,20 +1290,49 @@ func (b *CCFeedbackReportBlock) unmarshal(rawPacket []byte) error {
 		return errIncorrectNumReports
 	}
 
-	b.MetricBlocks = make([]CCFeedbackMetricBlock, numReports)
+	if cap(b.MetricBlocks) >= numReports {
+		b.MetricBlocks = b.MetricBlocks[:numReports]
+	} else {
+		b.MetricBlocks = make([]CCFeedbackMetricBlock, numReports)
+	}
 	for i := int(0); i < numReports; i++ {
 		var mb CCFeedbackMetricBlock
 		offset := reportsOffset + 2*i
-		if err := mb.unmarshal(rawPacket[offset : offset+2]); err != nil {
+		if err := mb.unmarshalWithOptions(rawPacket[offset:offset+2], opts); err != nil {
 			return err
 		}
 		b.MetricBlocks[i] = mb
 	}
+
+	if padding := reportsOffset + 2*numReports; opts.RejectNonZeroPadding && numReports%2 != 0 && len(rawPacket) >= padding+metricBlockLength {
+		if rawPacket[padding] != 0 || rawPacket[padding+1] != 0 {
+			return errNonZeroPadding
+		}
+	}
 	return nil
 }
 
+// Arrival time offsets with a special meaning, see
+// https://www.rfc-editor.org/rfc/rfc8888.html#section-3.1
+const (
+	// ArrivalTimeOffsetOverRange reports an arrival more than 8189/1024
+	// seconds before the report timestamp
+	ArrivalTimeOffsetOverRange = maxArrivalTimeOffset - 1
+
+	// ArrivalTimeOffsetUnavailable reports an unavailable arrival time
+	ArrivalTimeOffsetUnavailable = maxArrivalTimeOffset
+)
+
 const (
 	metricBlockLength = 2
+
+	arrivalTimeOffsetBits = 13
+	maxArrivalTimeOffset  = 1<<arrivalTimeOffsetBits - 1
+
+	arrivalTimeOffsetsPerSecond = 1024
+
+	// The report timestamp holds the middle 32 bits of an NTP timestamp.
+	reportTimestampsPerSecond = 1 << 16
 )
 
 // CCFeedbackMetricBlock is a Feedback Metric Block
@@ -295,12 +1340,18 @@ type CCFeedbackMetricBlock struct {
 	Received bool
 	ECN      ECN
 
-	// Offset in 1/1024 seconds before Report Timestamp
+	// Offset in 1/1024 seconds before Report Timestamp, not milliseconds.
+	// Only the low 13 bits are encoded; 0x1FFE means over-range and 0x1FFF
+	// unavailable. See ArrivalDelay for the offset as a time.Duration.
 	ArrivalTimeOffset uint16
 }
 
 // Marshal encodes the Congestion Control Feedback Metric Block in binary
 func (b CCFeedbackMetricBlock) marshal() ([]byte, error) {
+	if b.ArrivalTimeOffset > maxArrivalTimeOffset {
+		return nil, fmt.Errorf("%w: %d exceeds %d-bit maximum %d", errArrivalTimeOffset, b.ArrivalTimeOffset, arrivalTimeOffsetBits, maxArrivalTimeOffset)
+	}
+
 	buf := make([]byte, 2)
 	r := uint16(0)
 	if b.Received {
@@ -308,15 +1359,15 @@ func (b CCFeedbackMetricBlock) marshal() ([]byte, error) {
 	}
 	dst, err := setNBitsOfUint16(0, 1, 0, r)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("received bit: %w", err)
 	}
 	dst, err = setNBitsOfUint16(dst, 2, 1, uint16(b.ECN))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("ECN: %w", err)
 	}
-	dst, err = setNBitsOfUint16(dst, 13, 3, b.ArrivalTimeOffset)
+	dst, err = setNBitsOfUint16(dst, arrivalTimeOffsetBits, 3, b.ArrivalTimeOffset)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("arrival time offset: %w", err)
 	}
 
 	binary.BigEndian.PutUint16(buf, dst)
@@ -325,16 +1376,528 @@ func (b CCFeedbackMetricBlock) marshal() ([]byte, error) {
 
 // Unmarshal decodes the Congestion Control Feedback Metric Block from binary
 func (b *CCFeedbackMetricBlock) unmarshal(rawPacket []byte) error {
+	return b.unmarshalWithOptions(rawPacket, DecodeOptions{})
+}
+
+func (b *CCFeedbackMetricBlock) unmarshalWithOptions(rawPacket []byte, opts DecodeOptions) error {
 	if len(rawPacket) != metricBlockLength {
 		return errMetricBlockLength
 	}
 	b.Received = rawPacket[0]&0x80 != 0
-	if !b.Received {
+	if !b.Received && !opts.PreserveNotReceivedBits {
 		b.ECN = ECNNonECT
 		b.ArrivalTimeOffset = 0
 		return nil
 	}
-	b.ECN = ECN(rawPacket[0] >> 5 & 0x03)
-	b.ArrivalTimeOffset = binary.BigEndian.Uint16(rawPacket) & 0x1FFF
+	raw := binary.BigEndian.Uint16(rawPacket)
+	ecn, err := getNBitsFromUint16(raw, 2, 1)
+	if err != nil {
+		return fmt.Errorf("ECN: %w", err)
+	}
+	b.ECN = ECN(ecn)
+	b.ArrivalTimeOffset, err = getNBitsFromUint16(raw, arrivalTimeOffsetBits, 3)
+	if err != nil {
+		return fmt.Errorf("arrival time offset: %w", err)
+	}
+	return nil
+}
+
+// HasArrivalTime reports whether the block carries a usable arrival time:
+// the packet was received and its ArrivalTimeOffset is neither over-range
+// (0x1FFE) nor unavailable (0x1FFF).
+func (b CCFeedbackMetricBlock) HasArrivalTime() bool {
+	return b.Received && b.ArrivalTimeOffset < ArrivalTimeOffsetOverRange
+}
+
+// ArrivalDelay returns how long before the report timestamp the packet
+// arrived, converting ArrivalTimeOffset from 1/1024 seconds. The result is
+// only meaningful if HasArrivalTime is true.
+func (b CCFeedbackMetricBlock) ArrivalDelay() time.Duration {
+	return time.Duration(b.ArrivalTimeOffset) * time.Second / arrivalTimeOffsetsPerSecond
+}
+
+// RawArrivalOffset returns ArrivalTimeOffset, the arrival time offset as
+// encoded on the wire: a count of 1/1024 seconds, including the over-range
+// (0x1FFE) and unavailable (0x1FFF) values. Use ArrivalDelay for the offset
+// as a time.Duration.
+func (b CCFeedbackMetricBlock) RawArrivalOffset() uint16 {
+	return b.ArrivalTimeOffset
+}
+
+// PacketArrival describes the reception of a single RTP packet, as tracked
+// by a congestion controller.
+type PacketArrival struct {
+	SequenceNumber uint16
+	Received       bool
+	ECN            ECN
+
+	// Time the packet arrived. The zero value means it is not available.
+	Arrival time.Time
+}
+
+// OverflowPolicy selects how arrival times too long before the report
+// timestamp to fit into an arrival time offset are reported
+type OverflowPolicy uint8
+
+const (
+	// OverflowClamp clamps the offset to over-range (0x1FFE), the value
+	// RFC 8888 reserves for arrival times too long before the report
+	// timestamp. It is the default.
+	OverflowClamp OverflowPolicy = iota
+
+	// OverflowError fails the conversion
+	OverflowError
+)
+
+// NewCCFeedbackReportBlocks converts arrivals of mediaSSRC, sorted by
+// sequence number, into report blocks. Sequence numbers missing from
+// arrivals are reported as not received. Arrival time offsets are computed
+// relative to reportTime, the time the report timestamp refers to. Arrival
+// times 8189.5/1024 seconds or more before it are reported as over-range
+// (0x1FFE), and zero arrival times or ones after reportTime as unavailable
+// (0x1FFF). Ranges longer than MaxMetricBlocksPerReport or wrapping past
+// sequence number 65535 are split into several blocks. As a report block can
+// not carry a single metric block, an error is returned if a packet would be
+// left alone in one, as for a single arrival or one on either side of a
+// wraparound; the caller should hold it back until the next packet arrives.
+func NewCCFeedbackReportBlocks(mediaSSRC uint32, reportTime time.Time, arrivals []PacketArrival) ([]CCFeedbackReportBlock, error) {
+	return NewCCFeedbackReportBlocksWithOptions(mediaSSRC, reportTime, arrivals, BuildOptions{})
+}
+
+// NewCCFeedbackReportBlocksWithPolicy is like NewCCFeedbackReportBlocks, but
+// arrival times too long before reportTime to fit into an offset are reported
+// as chosen by policy. Arrival times after reportTime are always reported as
+// unavailable.
+func NewCCFeedbackReportBlocksWithPolicy(mediaSSRC uint32, reportTime time.Time, arrivals []PacketArrival, policy OverflowPolicy) ([]CCFeedbackReportBlock, error) {
+	return NewCCFeedbackReportBlocksWithOptions(mediaSSRC, reportTime, arrivals, BuildOptions{Overflow: policy})
+}
+
+// BuildOptions controls how NewCCFeedbackReportBlocksWithOptions builds
+// report blocks. The zero value matches NewCCFeedbackReportBlocks.
+type BuildOptions struct {
+	// Overflow selects how arrival times too long before the report time to
+	// fit into an offset are reported.
+	Overflow OverflowPolicy
+
+	// OmitAllLost leaves out report blocks in which no packet was received,
+	// such as during a total outage of the stream. This saves two bytes per
+	// packet, but the sender no longer learns that those packets were lost:
+	// sequence numbers a report does not cover carry no feedback at all.
+	// Keep the blocks if the sender expects consecutive reports to cover a
+	// continuous range of sequence numbers, for example to detect outages.
+	OmitAllLost bool
+}
+
+// NewCCFeedbackReportBlocksWithOptions is like NewCCFeedbackReportBlocks,
+// using opts to control how the report blocks are built.
+func NewCCFeedbackReportBlocksWithOptions(mediaSSRC uint32, reportTime time.Time, arrivals []PacketArrival, opts BuildOptions) ([]CCFeedbackReportBlock, error) {
+	if len(arrivals) == 0 {
+		return nil, nil
+	}
+
+	begin := arrivals[0].SequenceNumber
+	metricBlocks := make([]CCFeedbackMetricBlock, int(arrivals[len(arrivals)-1].SequenceNumber-begin)+1)
+	for _, arrival := range arrivals {
+		i := int(arrival.SequenceNumber - begin)
+		if i >= len(metricBlocks) {
+			continue
+		}
+		if arrival.Received {
+			offset, err := arrivalTimeOffset(reportTime, arrival.Arrival, opts.Overflow)
+			if err != nil {
+				return nil, fmt.Errorf("sequence number %d: %w", arrival.SequenceNumber, err)
+			}
+			metricBlocks[i] = CCFeedbackMetricBlock{
+				Received:          true,
+				ECN:               arrival.ECN,
+				ArrivalTimeOffset: offset,
+			}
+		}
+	}
+
+	blocks := splitMetricBlocks(mediaSSRC, begin, metricBlocks)
+	for _, block := range blocks {
+		if len(block.MetricBlocks) == 1 {
+			return nil, fmt.Errorf("sequence number %d: %w", block.BeginSequence, errSingleMetricBlock)
+		}
+	}
+	if !opts.OmitAllLost {
+		return blocks, nil
+	}
+	kept := blocks[:0]
+	for _, block := range blocks {
+		for _, mb := range block.MetricBlocks {
+			if mb.Received {
+				kept = append(kept, block)
+				break
+			}
+		}
+	}
+	if len(kept) == 0 {
+		return nil, nil
+	}
+	return kept, nil
+}
+
+// SetTimestampFromArrivals sets the report timestamp to now and replaces the
+// report blocks with ones built from arrivals by NewCCFeedbackReportBlocks,
+// with arrival time offsets computed backwards from now, so the two can not
+// drift apart. The arrivals of every media source must be sorted by sequence
+// number and may continue past a sequence number wraparound; the report
+// blocks are ordered by MediaSSRC. If the arrivals of a source can not be
+// reported, an error is returned and the report is left unchanged.
+func (b *CCFeedbackReport) SetTimestampFromArrivals(now time.Time, arrivals map[uint32][]PacketArrival) error {
+	ssrcs := make([]uint32, 0, len(arrivals))
+	for ssrc := range arrivals {
+		ssrcs = append(ssrcs, ssrc)
+	}
+	sort.Slice(ssrcs, func(i, j int) bool { return ssrcs[i] < ssrcs[j] })
+
+	blocks := []CCFeedbackReportBlock{}
+	for _, ssrc := range ssrcs {
+		ssrcBlocks, err := NewCCFeedbackReportBlocks(ssrc, now, arrivals[ssrc])
+		if err != nil {
+			return fmt.Errorf("media SSRC %d: %w", ssrc, err)
+		}
+		blocks = append(blocks, ssrcBlocks...)
+	}
+
+	// The report timestamp is the middle 32 bits of the NTP timestamp. It
+	// truncates now by less than 1/65536 seconds, much less than the
+	// resolution of the offsets.
+	b.ReportTimestamp = uint32(ToNTPTime(now) >> 16)
+	b.ReportBlocks = blocks
 	return nil
 }
+
+// NewCCFeedbackReportForStream builds a report carrying metricBlocks, the
+// feedback on consecutive packets of mediaSSRC starting at sequence number
+// begin. The packets are spread over as many report blocks as needed: a new
+// one starts after MaxMetricBlocksPerReport metric blocks and at a sequence
+// number wraparound, and each continues at the sequence number the previous
+// one ended. As a report block can not carry exactly one metric block, a
+// block is shortened by one to leave two for the next one where possible;
+// an error is returned if a wraparound leaves a single packet on either
+// side. An error is also returned if metricBlocks covers more than the 65536
+// sequence numbers or the report would exceed the maximum RTCP packet
+// length.
+func NewCCFeedbackReportForStream(senderSSRC, mediaSSRC uint32, begin uint16, metricBlocks []CCFeedbackMetricBlock, reportTimestamp uint32) (*CCFeedbackReport, error) {
+	if len(metricBlocks) > math.MaxUint16+1 {
+		return nil, errTooManyMetricBlocks
+	}
+
+	blocks := splitMetricBlocks(mediaSSRC, begin, metricBlocks)
+	for _, block := range blocks {
+		if len(block.MetricBlocks) == 1 {
+			return nil, fmt.Errorf("sequence number %d: %w", block.BeginSequence, errSingleMetricBlock)
+		}
+	}
+
+	report := NewCCFeedbackReport(senderSSRC, reportTimestamp, blocks)
+	if report.MarshalSize() > maxPacketLength {
+		return nil, errPacketTooLarge
+	}
+	return report, nil
+}
+
+// NewLossReport builds a report on mediaSSRC that marks the sequence numbers
+// in lostSeqs as lost and all others from begin up to the last lost one as
+// received, without an arrival time (0x1FFF). It lets a receiver signal a
+// burst of losses without tracking arrival times. lostSeqs must not be
+// before begin and may be in any order. A range that wraps past 65535 is
+// split into two report blocks at the wrap. An error is returned if the
+// range exceeds MaxMetricBlocksPerReport or a report block would be invalid.
+// Like NewCCFeedbackReportBlocks, this includes a block left with a single
+// metric block, as for a single loss at begin.
+func NewLossReport(senderSSRC, mediaSSRC uint32, begin uint16, lostSeqs []uint16, ts uint32) (*CCFeedbackReport, error) {
+	span := 0
+	for _, seq := range lostSeqs {
+		distance := int(seq - begin)
+		if distance >= 1<<15 {
+			return nil, fmt.Errorf("sequence number %d: %w", seq, errLostBeforeBegin)
+		}
+		if distance >= span {
+			span = distance + 1
+		}
+	}
+	if span == 0 {
+		return nil, errNoMetricBlocks
+	}
+	if span > MaxMetricBlocksPerReport {
+		return nil, errTooManyReports
+	}
+
+	metricBlocks := make([]CCFeedbackMetricBlock, span)
+	for i := range metricBlocks {
+		metricBlocks[i] = CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: ArrivalTimeOffsetUnavailable}
+	}
+	for _, seq := range lostSeqs {
+		metricBlocks[seq-begin] = CCFeedbackMetricBlock{}
+	}
+
+	blocks := []CCFeedbackReportBlock{{MediaSSRC: mediaSSRC, BeginSequence: begin, MetricBlocks: metricBlocks}}
+	if beforeWrap := math.MaxUint16 + 1 - int(begin); span > beforeWrap {
+		blocks = []CCFeedbackReportBlock{
+			{MediaSSRC: mediaSSRC, BeginSequence: begin, MetricBlocks: metricBlocks[:beforeWrap:beforeWrap]},
+			{MediaSSRC: mediaSSRC, BeginSequence: 0, MetricBlocks: metricBlocks[beforeWrap:]},
+		}
+	}
+	for i := range blocks {
+		if err := blocks[i].Validate(); err != nil {
+			return nil, fmt.Errorf("sequence number %d: %w", blocks[i].BeginSequence, err)
+		}
+	}
+	return NewCCFeedbackReport(senderSSRC, ts, blocks), nil
+}
+
+// QuantizeArrival converts d, how long before the report timestamp a packet
+// arrived, to an arrival time offset in 1/1024 seconds, rounded to the
+// nearest unit. residual is the rounding error, d minus the ArrivalDelay of
+// the offset, which controllers can carry over to avoid drift. ok is false
+// if d does not fit into the 13-bit range; offset is then over-range
+// (0x1FFE) for a d of 8189.5/1024 seconds or more and unavailable (0x1FFF)
+// for a negative d, and residual is 0.
+func QuantizeArrival(d time.Duration) (offset uint16, residual time.Duration, ok bool) {
+	if d < 0 {
+		return ArrivalTimeOffsetUnavailable, 0, false
+	}
+	if d >= ArrivalTimeOffsetOverRange*time.Second/arrivalTimeOffsetsPerSecond {
+		return ArrivalTimeOffsetOverRange, 0, false
+	}
+	units := (d*arrivalTimeOffsetsPerSecond + time.Second/2) / time.Second
+	if units >= ArrivalTimeOffsetOverRange {
+		return ArrivalTimeOffsetOverRange, 0, false
+	}
+	offset = uint16(units)
+	return offset, d - CCFeedbackMetricBlock{ArrivalTimeOffset: offset}.ArrivalDelay(), true
+}
+
+// arrivalTimeOffset returns the offset of arrival before reportTime in
+// 1/1024 seconds, rounded to the nearest unit. Offsets that do not fit are
+// handled according to policy.
+func arrivalTimeOffset(reportTime, arrival time.Time, policy OverflowPolicy) (uint16, error) {
+	if arrival.IsZero() {
+		return ArrivalTimeOffsetUnavailable, nil
+	}
+
+	delay := reportTime.Sub(arrival)
+	if delay < 0 {
+		return ArrivalTimeOffsetUnavailable, nil
+	}
+	if offset, _, ok := QuantizeArrival(delay); ok {
+		return offset, nil
+	}
+
+	if policy == OverflowError {
+		return 0, fmt.Errorf("%w: arrival %v before the report", errArrivalTimeOffset, delay)
+	}
+	return ArrivalTimeOffsetOverRange, nil
+}
+
+// PacketArrivals converts the block back into one PacketArrival per reported
+// sequence number, with arrival times relative to reportTime. Packets whose
+// arrival time is over-range or unavailable have a zero Arrival.
+func (b CCFeedbackReportBlock) PacketArrivals(reportTime time.Time) []PacketArrival {
+	arrivals := make([]PacketArrival, len(b.MetricBlocks))
+	for i, mb := range b.MetricBlocks {
+		arrivals[i] = PacketArrival{
+			SequenceNumber: b.BeginSequence + uint16(i),
+			Received:       mb.Received,
+		}
+		if !mb.Received {
+			continue
+		}
+		arrivals[i].ECN = mb.ECN
+		if mb.HasArrivalTime() {
+			arrivals[i].Arrival = reportTime.Add(-mb.ArrivalDelay())
+		}
+	}
+	return arrivals
+}
+
+// CCFeedbackToTransportLayerCC converts r into transport-wide congestion
+// control feedback. TWCC describes a single stream, so all report blocks
+// that carry metric blocks must have the same MediaSSRC; gaps between them
+// are reported as not received. ECN markings can not be expressed and are
+// dropped. The reference time is derived from the report timestamp and
+// every received packet must have an arrival time.
+func CCFeedbackToTransportLayerCC(r *CCFeedbackReport) (*TransportLayerCC, error) {
+	if err := r.ValidateRFC8888(); err != nil {
+		return nil, err
+	}
+
+	var (
+		mediaSSRC uint32
+		begin     uint16
+		metrics   []CCFeedbackMetricBlock
+	)
+	for _, block := range r.ReportBlocks {
+		if len(block.MetricBlocks) == 0 {
+			continue
+		}
+		if metrics == nil {
+			mediaSSRC, begin = block.MediaSSRC, block.BeginSequence
+		} else if block.MediaSSRC != mediaSSRC {
+			return nil, errMultipleMediaSSRCs
+		}
+		for gap := int(block.BeginSequence-begin) - len(metrics); gap > 0; gap-- {
+			metrics = append(metrics, CCFeedbackMetricBlock{})
+		}
+		metrics = append(metrics, block.MetricBlocks...)
+	}
+	if metrics == nil {
+		return nil, errNoMetricBlocks
+	}
+
+	// Arrival times are kept in units of the TWCC delta scale factor. The
+	// report timestamp and arrival time offsets are exact multiples of
+	// 1/1024us, so they are scaled to that first and only the arrival time
+	// is rounded down.
+	const (
+		unit         = TypeTCCDeltaScaleFactor * 1024
+		maxRunLength = 1<<13 - 1
+	)
+	reportTime := int64(r.ReportTimestamp) * 15625
+	symbols := make([]uint16, len(metrics))
+	var (
+		deltas   []*RecvDelta
+		previous int64
+	)
+	t := &TransportLayerCC{
+		SenderSSRC:         r.SenderSSRC,
+		MediaSSRC:          mediaSSRC,
+		BaseSequenceNumber: begin,
+		PacketStatusCount:  uint16(len(metrics)),
+	}
+	for i, mb := range metrics {
+		if !mb.Received {
+			symbols[i] = TypeTCCPacketNotReceived
+			continue
+		}
+		if !mb.HasArrivalTime() {
+			return nil, fmt.Errorf("sequence number %d: %w", begin+uint16(i), errMissingArrivalTime)
+		}
+
+		arrival := floorDiv(reportTime-int64(mb.ArrivalTimeOffset)*15625*64, unit)
+		if deltas == nil {
+			// The reference time counts multiples of 64ms, which are 256
+			// delta units.
+			reference := floorDiv(arrival, 256)
+			t.ReferenceTime = uint32(reference) & 0xFFFFFF
+			previous = reference * 256
+		}
+		delta := arrival - previous
+		previous = arrival
+
+		symbol := uint16(TypeTCCPacketReceivedSmallDelta)
+		if delta < 0 || delta > math.MaxUint8 {
+			symbol = TypeTCCPacketReceivedLargeDelta
+		}
+		symbols[i] = symbol
+		deltas = append(deltas, &RecvDelta{Type: symbol, Delta: delta * TypeTCCDeltaScaleFactor})
+	}
+	t.RecvDeltas = deltas
+
+	for i := 0; i < len(symbols); {
+		run := 1
+		for i+run < len(symbols) && symbols[i+run] == symbols[i] && run < maxRunLength {
+			run++
+		}
+		t.PacketChunks = append(t.PacketChunks, &RunLengthChunk{
+			Type:               TypeTCCRunLengthChunk,
+			PacketStatusSymbol: symbols[i],
+			RunLength:          uint16(run),
+		})
+		i += run
+	}
+
+	t.Header = Header{
+		Padding: t.packetLen()%4 != 0,
+		Count:   FormatTCC,
+		Type:    TypeTransportSpecificFeedback,
+		Length:  uint16(t.MarshalSize()/4 - 1),
+	}
+	return t, nil
+}
+
+// floorDiv divides a by b, rounding towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+type ccFeedbackReportJSON struct {
+	SenderSSRC      uint32                  `json:"senderSsrc"`
+	ReportTimestamp uint32                  `json:"reportTimestamp"`
+	ReportBlocks    []CCFeedbackReportBlock `json:"reportBlocks"`
+}
+
+type ccFeedbackReportBlockJSON struct {
+	MediaSSRC     uint32                      `json:"mediaSsrc"`
+	BeginSequence uint16                      `json:"beginSequence"`
+	MetricBlocks  []ccFeedbackMetricBlockJSON `json:"metricBlocks"`
+}
+
+type ccFeedbackMetricBlockJSON struct {
+	Sequence *uint16 `json:"sequence,omitempty"`
+	Received bool    `json:"received"`
+	ECN      string  `json:"ecn,omitempty"`
+
+	// Omitted if the arrival time is over-range or unavailable.
+	ArrivalTimeOffsetMs *float64 `json:"arrivalTimeOffsetMs,omitempty"`
+}
+
+// MarshalJSON encodes the report as JSON for logging. See
+// CCFeedbackReportBlock.MarshalJSON for the encoding of the report blocks.
+// The encoding is meant for humans and can not be decoded back.
+func (b CCFeedbackReport) MarshalJSON() ([]byte, error) {
+	blocks := b.ReportBlocks
+	if blocks == nil {
+		blocks = []CCFeedbackReportBlock{}
+	}
+	return json.Marshal(ccFeedbackReportJSON{
+		SenderSSRC:      b.SenderSSRC,
+		ReportTimestamp: b.ReportTimestamp,
+		ReportBlocks:    blocks,
+	})
+}
+
+// MarshalJSON encodes the report block as JSON for logging. Every metric
+// block is annotated with the sequence number it reports on; the padding
+// that aligns the block on the wire is not included.
+func (b CCFeedbackReportBlock) MarshalJSON() ([]byte, error) {
+	metricBlocks := make([]ccFeedbackMetricBlockJSON, len(b.MetricBlocks))
+	for i, mb := range b.MetricBlocks {
+		seq := b.BeginSequence + uint16(i)
+		metricBlocks[i] = mb.jsonValue()
+		metricBlocks[i].Sequence = &seq
+	}
+	return json.Marshal(ccFeedbackReportBlockJSON{
+		MediaSSRC:     b.MediaSSRC,
+		BeginSequence: b.BeginSequence,
+		MetricBlocks:  metricBlocks,
+	})
+}
+
+// MarshalJSON encodes the metric block as JSON for logging, with the ECN
+// marking by name and the arrival time offset in milliseconds. Both are
+// only present for received packets.
+func (b CCFeedbackMetricBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.jsonValue())
+}
+
+func (b CCFeedbackMetricBlock) jsonValue() ccFeedbackMetricBlockJSON {
+	v := ccFeedbackMetricBlockJSON{Received: b.Received}
+	if !b.Received {
+		return v
+	}
+	v.ECN = b.ECN.String()
+	if b.HasArrivalTime() {
+		ms := float64(b.ArrivalTimeOffset) * 1000 / arrivalTimeOffsetsPerSecond
+		v.ArrivalTimeOffsetMs = &ms
+	}
+	return v
+}
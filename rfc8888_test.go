@@ -5,13 +5,23 @@ package rtcp
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
-var _ Packet = (*CCFeedbackReport)(nil) // assert is a Packet
+var (
+	_ Packet      = (*CCFeedbackReport)(nil) // assert is a Packet
+	_ io.WriterTo = CCFeedbackReport{}       // assert is an io.WriterTo
+)
 
 func TestCCFeedbackMetricBlockUnmarshalMarshal(t *testing.T) {
 	for _, test := range []struct {
@@ -131,6 +141,16 @@ func TestCCFeedbackMetricBlockUnmarshalMarshal(t *testing.T) {
 			assert.ErrorIs(t, err, errMetricBlockLength)
 		})
 	}
+
+	t.Run("MarshalArrivalTimeOffsetTooLarge", func(t *testing.T) {
+		block := CCFeedbackMetricBlock{
+			Received:          true,
+			ArrivalTimeOffset: 9000,
+		}
+		_, err := block.marshal()
+		assert.ErrorIs(t, err, errArrivalTimeOffset)
+		assert.EqualError(t, err, "arrival time offset out of range: 9000 exceeds 13-bit maximum 8191")
+	})
 }
 
 func TestCCFeedbackReportBlockUnmarshalMarshal(t *testing.T) {
@@ -294,6 +314,52 @@ func TestCCFeedbackReportBlockUnmarshalMarshal(t *testing.T) {
 	})
 }
 
+func TestCCFeedbackReportBlockSplitByMaxReports(t *testing.T) {
+	for _, test := range []struct {
+		Name  string
+		Begin uint16
+		Count int
+		Sizes []int
+	}{
+		{Name: "AtLimit", Begin: 100, Count: MaxMetricBlocksPerReport, Sizes: []int{16384}},
+		{Name: "OneOverLimit", Begin: 100, Count: MaxMetricBlocksPerReport + 1, Sizes: []int{16383, 2}},
+		{Name: "TwoOverLimit", Begin: 100, Count: MaxMetricBlocksPerReport + 2, Sizes: []int{16384, 2}},
+		{Name: "Wrap", Begin: 0xFFF0, Count: 100, Sizes: []int{16, 84}},
+		{Name: "OneOverLimitAcrossWrap", Begin: 0xFFF0, Count: MaxMetricBlocksPerReport + 1, Sizes: []int{16, 16369}},
+		{Name: "OverLimitAfterWrap", Begin: 0xFFF0, Count: MaxMetricBlocksPerReport + 17, Sizes: []int{16, 16383, 2}},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			block := CCFeedbackReportBlock{
+				MediaSSRC:     1,
+				BeginSequence: test.Begin,
+				MetricBlocks:  make([]CCFeedbackMetricBlock, test.Count),
+			}
+			block.MetricBlocks[test.Count-1] = CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: 1}
+
+			blocks := block.SplitByMaxReports()
+			seq := test.Begin
+			if assert.Len(t, blocks, len(test.Sizes)) {
+				for i, b := range blocks {
+					assert.Equal(t, uint32(1), b.MediaSSRC)
+					assert.Equal(t, seq, b.BeginSequence, "block %d", i)
+					assert.Len(t, b.MetricBlocks, test.Sizes[i], "block %d", i)
+					assert.NoError(t, b.Validate(), "block %d", i)
+					seq += uint16(len(b.MetricBlocks))
+				}
+			}
+
+			// Every block decodes back to the packets it carries.
+			report := NewCCFeedbackReport(2, 3, blocks)
+			data, err := report.Marshal()
+			assert.NoError(t, err)
+			var decoded CCFeedbackReport
+			assert.NoError(t, decoded.Unmarshal(data))
+			assert.Equal(t, *report, decoded)
+		})
+	}
+}
+
 func TestCCFeedbackReportUnmarshalMarshal(t *testing.T) {
 	for _, test := range []struct {
 		Name string
@@ -412,14 +478,2829 @@ func TestCCFeedbackOverflow(t *testing.T) {
 	p := &CCFeedbackReport{}
 	err := p.Unmarshal(append([]byte{
 		// Header
-		0b10000000, // V = 2
+		0b10001011, // V = 2, FMT = 11
 		205,        // h.Type = TypeTransportSpecificFeedback
-		0, 0,       // h.Length (unused)
+		0x40, 0x00, // h.Length
 		// SSRC
 		0, 0, 0, 0,
 		// CCFeedbackReportBlock
 		0, 0, 0, 0, 0, 0,
 		0x7F, 0xFB, // numReportsField
-	}, bytes.Repeat([]byte{0, 0}, 0x7FFF)...))
-	assert.ErrorIs(t, err, errReportBlockLength)
+	}, bytes.Repeat([]byte{0, 0}, 0x7FFA)...))
+	assert.ErrorIs(t, err, errIncorrectNumReports)
+}
+
+func TestCCFeedbackReportUnmarshalValidatesHeader(t *testing.T) {
+	for _, test := range []struct {
+		Name      string
+		Data      []byte
+		WantError error
+	}{
+		{
+			Name: "BadVersion",
+			Data: []byte{
+				0x4B, 0xCD, 0x00, 0x02, // V=1, P=0, FMT=11, PT=205, Length=2
+				0x00, 0x00, 0x00, 0x01, // Sender SSRC=1
+				0x00, 0x00, 0x00, 0x01, // Report Timestamp=1
+			},
+			WantError: errBadVersion,
+		},
+		{
+			Name: "LengthTooLarge",
+			Data: []byte{
+				0x8B, 0xCD, 0x00, 0x03, // V=2, P=0, FMT=11, PT=205, Length=3
+				0x00, 0x00, 0x00, 0x01, // Sender SSRC=1
+				0x00, 0x00, 0x00, 0x01, // Report Timestamp=1
+			},
+			WantError: errLengthMismatch,
+		},
+		{
+			Name: "LengthTooSmall",
+			Data: []byte{
+				0x8B, 0xCD, 0x00, 0x01, // V=2, P=0, FMT=11, PT=205, Length=1
+				0x00, 0x00, 0x00, 0x01, // Sender SSRC=1
+				0x00, 0x00, 0x00, 0x01, // Report Timestamp=1
+			},
+			WantError: errLengthMismatch,
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			var report CCFeedbackReport
+			assert.ErrorIs(t, report.Unmarshal(test.Data), test.WantError)
+		})
+	}
+}
+
+func TestCCFeedbackReportUnmarshalWithOptions(t *testing.T) {
+	data := []byte{
+		0x8B, 0xCD, 0x00, 0x05, // V=2, P=0, FMT=11, PT=205, Length=5
+		0x00, 0x00, 0x00, 0x01, // Sender SSRC=1
+		0x00, 0x00, 0x00, 0x02, // Media SSRC=2
+		0x00, 0x05, 0x00, 0x01, // begin_seq=5, num_reports=2
+		0x62, 0x01, 0x9F, 0xFD, // reports[0] (not received, ECN=CE, offset=513), reports[1]
+		0x00, 0x00, 0x00, 0x01, // Report Timestamp=1
+	}
+
+	t.Run("Canonicalize", func(t *testing.T) {
+		var report CCFeedbackReport
+		assert.NoError(t, report.UnmarshalWithOptions(data, DecodeOptions{}))
+		assert.Equal(t, CCFeedbackMetricBlock{}, report.ReportBlocks[0].MetricBlocks[0])
+
+		buf, err := report.Marshal()
+		assert.NoError(t, err)
+		assert.NotEqual(t, data, buf)
+	})
+
+	t.Run("PreserveNotReceivedBits", func(t *testing.T) {
+		var report CCFeedbackReport
+		assert.NoError(t, report.UnmarshalWithOptions(data, DecodeOptions{PreserveNotReceivedBits: true}))
+		assert.Equal(t, CCFeedbackMetricBlock{
+			Received:          false,
+			ECN:               ECNCE,
+			ArrivalTimeOffset: 513,
+		}, report.ReportBlocks[0].MetricBlocks[0])
+
+		buf, err := report.Marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, data, buf)
+	})
+}
+
+func TestCCFeedbackReportUnmarshalRejectNonZeroPadding(t *testing.T) {
+	for _, test := range []struct {
+		Name    string
+		Padding []byte
+		WantErr error
+	}{
+		{Name: "zero", Padding: []byte{0x00, 0x00}},
+		{Name: "non-zero first octet", Padding: []byte{0x80, 0x00}, WantErr: errNonZeroPadding},
+		{Name: "non-zero second octet", Padding: []byte{0x00, 0x01}, WantErr: errNonZeroPadding},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			data := []byte{
+				0x8B, 0xCD, 0x00, 0x06, // V=2, P=0, FMT=11, PT=205, Length=6
+				0x00, 0x00, 0x00, 0x01, // Sender SSRC=1
+				0x00, 0x00, 0x00, 0x02, // Media SSRC=2
+				0x00, 0x05, 0x00, 0x02, // begin_seq=5, num_reports=3
+				0x80, 0x01, 0x80, 0x02, // reports[0], reports[1]
+				0x80, 0x03, test.Padding[0], test.Padding[1], // reports[2], padding
+				0x00, 0x00, 0x00, 0x01, // Report Timestamp=1
+			}
+
+			var report CCFeedbackReport
+			assert.NoError(t, report.UnmarshalWithOptions(data, DecodeOptions{}))
+			assert.Len(t, report.ReportBlocks[0].MetricBlocks, 3)
+
+			err := report.UnmarshalWithOptions(data, DecodeOptions{RejectNonZeroPadding: true})
+			assert.ErrorIs(t, err, test.WantErr)
+		})
+	}
+
+	t.Run("even count", func(t *testing.T) {
+		data := []byte{
+			0x8B, 0xCD, 0x00, 0x05, // V=2, P=0, FMT=11, PT=205, Length=5
+			0x00, 0x00, 0x00, 0x01, // Sender SSRC=1
+			0x00, 0x00, 0x00, 0x02, // Media SSRC=2
+			0x00, 0x05, 0x00, 0x01, // begin_seq=5, num_reports=2
+			0x80, 0x01, 0x80, 0x02, // reports[0], reports[1]
+			0x00, 0x00, 0x00, 0x01, // Report Timestamp=1
+		}
+		var report CCFeedbackReport
+		assert.NoError(t, report.UnmarshalWithOptions(data, DecodeOptions{RejectNonZeroPadding: true}))
+	})
+}
+
+func TestCCFeedbackReportUnmarshalReuse(t *testing.T) {
+	large := CCFeedbackReport{
+		SenderSSRC: 1,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{
+				MediaSSRC:     2,
+				BeginSequence: 10,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNCE, ArrivalTimeOffset: 1},
+					{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 2},
+					{Received: true, ECN: ECNECT1, ArrivalTimeOffset: 3},
+				},
+			},
+			{
+				MediaSSRC:     3,
+				BeginSequence: 20,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ArrivalTimeOffset: 4},
+					{Received: false},
+				},
+			},
+		},
+		ReportTimestamp: 5,
+	}
+	small := CCFeedbackReport{
+		SenderSSRC: 6,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{
+				MediaSSRC:     7,
+				BeginSequence: 30,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: false},
+					{Received: true, ArrivalTimeOffset: 8},
+				},
+			},
+		},
+		ReportTimestamp: 9,
+	}
+
+	largeData, err := large.Marshal()
+	assert.NoError(t, err)
+	smallData, err := small.Marshal()
+	assert.NoError(t, err)
+
+	var report CCFeedbackReport
+	assert.NoError(t, report.Unmarshal(largeData))
+	assert.Equal(t, large, report)
+
+	reportBlock := &report.ReportBlocks[0]
+	metricBlock := &report.ReportBlocks[0].MetricBlocks[0]
+
+	assert.NoError(t, report.Unmarshal(smallData))
+	assert.Equal(t, small, report)
+	assert.Same(t, reportBlock, &report.ReportBlocks[0])
+	assert.Same(t, metricBlock, &report.ReportBlocks[0].MetricBlocks[0])
+
+	assert.NoError(t, report.Unmarshal(largeData))
+	assert.Equal(t, large, report)
+}
+
+func BenchmarkCCFeedbackReportUnmarshal(b *testing.B) {
+	report := CCFeedbackReport{
+		SenderSSRC: 1,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: make([]CCFeedbackMetricBlock, 64)},
+			{MediaSSRC: 3, BeginSequence: 20, MetricBlocks: make([]CCFeedbackMetricBlock, 31)},
+		},
+		ReportTimestamp: 5,
+	}
+	data, err := report.Marshal()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var decoded CCFeedbackReport
+			if err := decoded.Unmarshal(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Reused", func(b *testing.B) {
+		b.ReportAllocs()
+		var decoded CCFeedbackReport
+		for i := 0; i < b.N; i++ {
+			if err := decoded.Unmarshal(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkCCFeedbackReportBlockUnmarshal(b *testing.B) {
+	block := CCFeedbackReportBlock{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: make([]CCFeedbackMetricBlock, 64)}
+	data, err := block.marshal()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	// The metric blocks decoded by the first call are reused by the rest.
+	var decoded CCFeedbackReportBlock
+	for i := 0; i < b.N; i++ {
+		if err := decoded.unmarshal(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// newBenchmarkReport returns a report with the given number of report
+// blocks, whose metric block counts vary between 2 and 62, odd and even
+func newBenchmarkReport(blocks int) CCFeedbackReport {
+	report := CCFeedbackReport{SenderSSRC: 1, ReportTimestamp: 5}
+	for i := 0; i < blocks; i++ {
+		metricBlocks := make([]CCFeedbackMetricBlock, 2+(i*7)%61)
+		for j := range metricBlocks {
+			if j%3 != 0 {
+				metricBlocks[j] = CCFeedbackMetricBlock{Received: true, ECN: ECNECT0, ArrivalTimeOffset: uint16(j)}
+			}
+		}
+		report.ReportBlocks = append(report.ReportBlocks, CCFeedbackReportBlock{
+			MediaSSRC:     uint32(i),
+			BeginSequence: uint16(i * 100),
+			MetricBlocks:  metricBlocks,
+		})
+	}
+	return report
+}
+
+func BenchmarkCCFeedbackReportSizes(b *testing.B) {
+	for _, blocks := range []int{1, 16, 256} {
+		report := newBenchmarkReport(blocks)
+		data, err := report.Marshal()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprintf("Marshal/%d", blocks), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := report.Marshal(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("MarshalTo/%d", blocks), func(b *testing.B) {
+			b.ReportAllocs()
+			buf := make([]byte, report.MarshalSize())
+			for i := 0; i < b.N; i++ {
+				if _, err := report.MarshalTo(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("Unmarshal/%d", blocks), func(b *testing.B) {
+			b.ReportAllocs()
+			var decoded CCFeedbackReport
+			for i := 0; i < b.N; i++ {
+				if err := decoded.Unmarshal(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestCCFeedbackReportUnmarshalAllocs(t *testing.T) {
+	for _, blocks := range []int{1, 16, 256} {
+		report := newBenchmarkReport(blocks)
+		data, err := report.Marshal()
+		assert.NoError(t, err)
+
+		// A fresh report allocates the report block slice as it grows and
+		// one metric block slice per report block.
+		fresh := testing.AllocsPerRun(10, func() {
+			var decoded CCFeedbackReport
+			_ = decoded.Unmarshal(data)
+		})
+		assert.LessOrEqual(t, fresh, float64(2*blocks+10), "%d blocks", blocks)
+
+		// Decoding into the same report again reuses its slices.
+		var decoded CCFeedbackReport
+		assert.NoError(t, decoded.Unmarshal(data))
+		reused := testing.AllocsPerRun(10, func() {
+			_ = decoded.Unmarshal(data)
+		})
+		assert.Zero(t, reused, "%d blocks", blocks)
+	}
+}
+
+func TestCCFeedbackReportNoReportBlocks(t *testing.T) {
+	report := CCFeedbackReport{
+		SenderSSRC:      0x01020304,
+		ReportBlocks:    nil,
+		ReportTimestamp: 0x05060708,
+	}
+
+	assert.Equal(t, 12, report.MarshalSize())
+	assert.Equal(t, []uint32{}, report.DestinationSSRC())
+
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x8B, 0xCD, 0x00, 0x02, // V=2, P=0, FMT=11, PT=205, Length=2
+		0x01, 0x02, 0x03, 0x04, // Sender SSRC
+		0x05, 0x06, 0x07, 0x08, // Report Timestamp
+	}, data)
+
+	var decoded CCFeedbackReport
+	assert.NoError(t, decoded.Unmarshal(data))
+	assert.Equal(t, report.SenderSSRC, decoded.SenderSSRC)
+	assert.Equal(t, report.ReportTimestamp, decoded.ReportTimestamp)
+	assert.Empty(t, decoded.ReportBlocks)
+	assert.Equal(t, []uint32{}, decoded.DestinationSSRC())
+}
+
+// interopVectors are reports laid out the way other RFC 8888 implementations,
+// such as libwebrtc, send them. Add captured reports here to check that they
+// decode and that Marshal reproduces them.
+var interopVectors = []struct { //nolint:gochecknoglobals
+	Name string
+	Data []byte
+	Want CCFeedbackReport
+}{
+	{
+		Name: "even metric block count",
+		Data: []byte{
+			0x8B, 0xCD, 0x00, 0x06, // V=2, P=0, FMT=11, PT=205, Length=6
+			0xFA, 0x17, 0xFA, 0x17, // Sender SSRC
+			0x3C, 0x4D, 0x5E, 0x6F, // Media SSRC
+			0x12, 0x34, 0x00, 0x03, // begin_seq=0x1234, num_reports=3
+			0xA0, 0x40, 0xA0, 0x30, // R=1, ECT(1), offsets 64 and 48
+			0x00, 0x00, 0xA0, 0x00, // lost, R=1, ECT(1), offset 0
+			0x6F, 0x3B, 0x1A, 0x20, // Report Timestamp
+		},
+		Want: CCFeedbackReport{
+			SenderSSRC: 0xFA17FA17,
+			ReportBlocks: []CCFeedbackReportBlock{{
+				MediaSSRC:     0x3C4D5E6F,
+				BeginSequence: 0x1234,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNECT1, ArrivalTimeOffset: 64},
+					{Received: true, ECN: ECNECT1, ArrivalTimeOffset: 48},
+					{},
+					{Received: true, ECN: ECNECT1, ArrivalTimeOffset: 0},
+				},
+			}},
+			ReportTimestamp: 0x6F3B1A20,
+		},
+	},
+	{
+		Name: "odd metric block count",
+		Data: []byte{
+			0x8B, 0xCD, 0x00, 0x06, // V=2, P=0, FMT=11, PT=205, Length=6
+			0xFA, 0x17, 0xFA, 0x17, // Sender SSRC
+			0x3C, 0x4D, 0x5E, 0x6F, // Media SSRC
+			0xFF, 0xFC, 0x00, 0x02, // begin_seq=65532, num_reports=2
+			0xFF, 0xFE, 0x9F, 0xFF, // R=1, CE, over-range, R=1, no offset
+			0xA0, 0x01, 0x00, 0x00, // R=1, ECT(1), offset 1, padding
+			0x6F, 0x3B, 0x1A, 0x20, // Report Timestamp
+		},
+		Want: CCFeedbackReport{
+			SenderSSRC: 0xFA17FA17,
+			ReportBlocks: []CCFeedbackReportBlock{{
+				MediaSSRC:     0x3C4D5E6F,
+				BeginSequence: 65532,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNCE, ArrivalTimeOffset: 0x1FFE},
+					{Received: true, ECN: ECNNonECT, ArrivalTimeOffset: 0x1FFF},
+					{Received: true, ECN: ECNECT1, ArrivalTimeOffset: 1},
+				},
+			}},
+			ReportTimestamp: 0x6F3B1A20,
+		},
+	},
+	{
+		// The metric block that aligns an odd-sized report block is
+		// ignored on decode by default, and Marshal always writes it as
+		// zero.
+		Name: "odd metric block count with non-zero alignment",
+		Data: []byte{
+			0x8B, 0xCD, 0x00, 0x06, // V=2, P=0, FMT=11, PT=205, Length=6
+			0xFA, 0x17, 0xFA, 0x17, // Sender SSRC
+			0x3C, 0x4D, 0x5E, 0x6F, // Media SSRC
+			0x00, 0x07, 0x00, 0x02, // begin_seq=7, num_reports=2
+			0x00, 0x00, 0x00, 0x00, // lost, lost
+			0x80, 0x10, 0x80, 0x10, // R=1, Non-ECT, offset 16, alignment
+			0x6F, 0x3B, 0x1A, 0x20, // Report Timestamp
+		},
+		Want: CCFeedbackReport{
+			SenderSSRC: 0xFA17FA17,
+			ReportBlocks: []CCFeedbackReportBlock{{
+				MediaSSRC:     0x3C4D5E6F,
+				BeginSequence: 7,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{},
+					{},
+					{Received: true, ArrivalTimeOffset: 16},
+				},
+			}},
+			ReportTimestamp: 0x6F3B1A20,
+		},
+	},
+	{
+		Name: "multiple media sources",
+		Data: []byte{
+			0x8B, 0xCD, 0x00, 0x08, // V=2, P=0, FMT=11, PT=205, Length=8
+			0x00, 0x00, 0x00, 0x01, // Sender SSRC
+			0x00, 0x00, 0x00, 0x02, // Media SSRC
+			0x00, 0x01, 0x00, 0x01, // begin_seq=1, num_reports=1
+			0xC0, 0x20, 0xC0, 0x00, // R=1, ECT(0), offsets 32 and 0
+			0x00, 0x00, 0x00, 0x03, // Media SSRC
+			0x10, 0x00, 0x00, 0x01, // begin_seq=4096, num_reports=1
+			0x00, 0x00, 0xE0, 0x05, // lost, R=1, CE, offset 5
+			0x00, 0x01, 0x00, 0x00, // Report Timestamp
+		},
+		Want: CCFeedbackReport{
+			SenderSSRC: 1,
+			ReportBlocks: []CCFeedbackReportBlock{
+				{
+					MediaSSRC:     2,
+					BeginSequence: 1,
+					MetricBlocks: []CCFeedbackMetricBlock{
+						{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 32},
+						{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 0},
+					},
+				},
+				{
+					MediaSSRC:     3,
+					BeginSequence: 4096,
+					MetricBlocks: []CCFeedbackMetricBlock{
+						{},
+						{Received: true, ECN: ECNCE, ArrivalTimeOffset: 5},
+					},
+				},
+			},
+			ReportTimestamp: 0x00010000,
+		},
+	},
+}
+
+// zeroMetricBlockAlignment returns a copy of data, an encoded report decoded
+// as report, with the metric block aligning each odd-sized report block set
+// to zero, as Marshal writes it.
+func zeroMetricBlockAlignment(data []byte, report CCFeedbackReport) []byte {
+	out := append([]byte{}, data...)
+	offset := headerLength + ssrcLength
+	for i := range report.ReportBlocks {
+		block := &report.ReportBlocks[i]
+		if n := len(block.MetricBlocks); n%2 != 0 {
+			alignment := offset + reportsOffset + 2*n
+			out[alignment], out[alignment+1] = 0, 0
+		}
+		offset += block.len()
+	}
+	return out
+}
+
+func TestCCFeedbackReportInteropVectors(t *testing.T) {
+	for _, test := range interopVectors {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			var report CCFeedbackReport
+			assert.NoError(t, report.Unmarshal(test.Data))
+			assert.Equal(t, test.Want, report)
+			assert.NoError(t, report.ValidateRFC8888())
+
+			data, err := report.Marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, zeroMetricBlockAlignment(test.Data, report), data)
+		})
+	}
+}
+
+func TestCCFeedbackReportUnmarshalPadding(t *testing.T) {
+	t.Run("without report blocks", func(t *testing.T) {
+		var report CCFeedbackReport
+		assert.NoError(t, report.Unmarshal([]byte{
+			0xAB, 0xCD, 0x00, 0x03, // V=2, P=1, FMT=11, PT=205, Length=3
+			0x01, 0x02, 0x03, 0x04, // Sender SSRC
+			0x05, 0x06, 0x07, 0x08, // Report Timestamp
+			0x00, 0x00, 0x00, 0x04, // Padding
+		}))
+		assert.Equal(t, uint32(0x01020304), report.SenderSSRC)
+		assert.Equal(t, uint32(0x05060708), report.ReportTimestamp)
+		assert.Empty(t, report.ReportBlocks)
+	})
+
+	t.Run("with report block", func(t *testing.T) {
+		var report CCFeedbackReport
+		assert.NoError(t, report.Unmarshal([]byte{
+			0xAB, 0xCD, 0x00, 0x06, // V=2, P=1, FMT=11, PT=205, Length=6
+			0x01, 0x02, 0x03, 0x04, // Sender SSRC
+			0x11, 0x12, 0x13, 0x14, // Media SSRC
+			0x00, 0x10, 0x00, 0x01, // Begin Sequence, Num Reports
+			0x80, 0x01, 0x80, 0x02, // Metric Blocks
+			0x05, 0x06, 0x07, 0x08, // Report Timestamp
+			0x00, 0x00, 0x00, 0x04, // Padding
+		}))
+		assert.Equal(t, uint32(0x05060708), report.ReportTimestamp)
+		assert.Equal(t, []CCFeedbackReportBlock{{
+			MediaSSRC:     0x11121314,
+			BeginSequence: 0x0010,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNNonECT, ArrivalTimeOffset: 1},
+				{Received: true, ECN: ECNNonECT, ArrivalTimeOffset: 2},
+			},
+		}}, report.ReportBlocks)
+	})
+
+	for name, data := range map[string][]byte{
+		"zero padding count": {
+			0xAB, 0xCD, 0x00, 0x03,
+			0x01, 0x02, 0x03, 0x04,
+			0x05, 0x06, 0x07, 0x08,
+			0x00, 0x00, 0x00, 0x00,
+		},
+		"padding overlaps timestamp": {
+			0xAB, 0xCD, 0x00, 0x03,
+			0x01, 0x02, 0x03, 0x04,
+			0x05, 0x06, 0x07, 0x08,
+			0x00, 0x00, 0x00, 0x08,
+		},
+	} {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			var report CCFeedbackReport
+			assert.ErrorIs(t, report.Unmarshal(data), errLengthMismatch)
+		})
+	}
+}
+
+func TestCCFeedbackReportValidateRFC8888(t *testing.T) {
+	received := func(n int) []CCFeedbackMetricBlock {
+		mbs := make([]CCFeedbackMetricBlock, n)
+		for i := range mbs {
+			mbs[i] = CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: uint16(i)}
+		}
+		return mbs
+	}
+
+	for _, test := range []struct {
+		Name      string
+		Blocks    []CCFeedbackReportBlock
+		WantError error
+	}{
+		{
+			Name: "Valid",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: received(4)},
+				{MediaSSRC: 2, BeginSequence: 0, MetricBlocks: received(4)},
+				{MediaSSRC: 1, BeginSequence: 14, MetricBlocks: received(2)},
+			},
+		},
+		{
+			Name: "ValidAcrossWraparound",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 0xFFFE, MetricBlocks: received(2)},
+				{MediaSSRC: 1, BeginSequence: 0, MetricBlocks: received(2)},
+			},
+		},
+		{
+			Name: "TooManyMetricBlocks",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, MetricBlocks: make([]CCFeedbackMetricBlock, MaxMetricBlocksPerReport+1)},
+			},
+			WantError: errTooManyReports,
+		},
+		{
+			Name: "ArrivalTimeOffsetTooLarge",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{{Received: true, ArrivalTimeOffset: 0x2000}}},
+			},
+			WantError: errArrivalTimeOffset,
+		},
+		{
+			Name: "OutOfOrder",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: received(2)},
+				{MediaSSRC: 1, BeginSequence: 5, MetricBlocks: received(2)},
+			},
+			WantError: errReportBlockOrder,
+		},
+		{
+			Name: "Overlapping",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: received(4)},
+				{MediaSSRC: 1, BeginSequence: 13, MetricBlocks: received(2)},
+			},
+			WantError: errReportBlockOverlap,
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			report := CCFeedbackReport{ReportBlocks: test.Blocks}
+			assert.ErrorIs(t, report.ValidateRFC8888(), test.WantError)
+		})
+	}
+}
+
+func TestNewCCFeedbackReport(t *testing.T) {
+	report := NewCCFeedbackReport(1, 2, []CCFeedbackReportBlock{
+		{MediaSSRC: 3, BeginSequence: 4, MetricBlocks: []CCFeedbackMetricBlock{{Received: true}, {Received: false}}},
+	})
+	assert.Equal(t, Header{
+		Count:  FormatCCFB,
+		Type:   TypeTransportSpecificFeedback,
+		Length: 5,
+	}, report.Header())
+
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x8B, 0xCD}, data[:2])
+
+	var decoded CCFeedbackReport
+	assert.NoError(t, decoded.Unmarshal(data))
+
+	// FMT=15 (TransportLayerCC) instead of FMT=11
+	data[0] = 0x8F
+	assert.ErrorIs(t, decoded.Unmarshal(data), errWrongType)
+
+	// PT=206 instead of PT=205
+	data[0], data[1] = 0x8B, 0xCE
+	assert.ErrorIs(t, decoded.Unmarshal(data), errWrongType)
+}
+
+func TestCCFeedbackPacketArrivals(t *testing.T) {
+	reportTime := time.Unix(1000, 0)
+	unit := time.Second / 512 // two arrival time offset units
+
+	arrivals := []PacketArrival{
+		{SequenceNumber: 0xFFFE, Received: true, ECN: ECNECT0, Arrival: reportTime.Add(-1024 * unit)},
+		{SequenceNumber: 0xFFFF, Received: false},
+		{SequenceNumber: 0x0000, Received: true, ECN: ECNCE, Arrival: reportTime.Add(-unit)},
+		// 0x0001 is missing and reported as lost
+		{SequenceNumber: 0x0002, Received: true, ECN: ECNNonECT, Arrival: reportTime},
+	}
+
+	// The range is split at the sequence number wraparound.
+	blocks, err := NewCCFeedbackReportBlocks(7, reportTime, arrivals)
+	assert.NoError(t, err)
+	assert.Equal(t, []CCFeedbackReportBlock{
+		{
+			MediaSSRC:     7,
+			BeginSequence: 0xFFFE,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 2048},
+				{Received: false},
+			},
+		},
+		{
+			MediaSSRC:     7,
+			BeginSequence: 0x0000,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNCE, ArrivalTimeOffset: 2},
+				{Received: false},
+				{Received: true, ECN: ECNNonECT, ArrivalTimeOffset: 0},
+			},
+		},
+	}, blocks)
+
+	assert.Equal(t, []PacketArrival{
+		arrivals[0],
+		arrivals[1],
+		arrivals[2],
+		{SequenceNumber: 0x0001, Received: false},
+		arrivals[3],
+	}, append(blocks[0].PacketArrivals(reportTime), blocks[1].PacketArrivals(reportTime)...))
+
+	t.Run("Unavailable", func(t *testing.T) {
+		blocks, err := NewCCFeedbackReportBlocks(7, reportTime, []PacketArrival{
+			{SequenceNumber: 1, Received: true, Arrival: reportTime.Add(time.Millisecond)},
+			{SequenceNumber: 2, Received: true},
+			{SequenceNumber: 3, Received: true, Arrival: reportTime.Add(-8189 * time.Second / 1024)},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, blocks, 1)
+		assert.Equal(t, []CCFeedbackMetricBlock{
+			{Received: true, ArrivalTimeOffset: 0x1FFF},
+			{Received: true, ArrivalTimeOffset: 0x1FFF},
+			{Received: true, ArrivalTimeOffset: 0x1FFD},
+		}, blocks[0].MetricBlocks)
+
+		for _, arrival := range blocks[0].PacketArrivals(reportTime)[:2] {
+			assert.True(t, arrival.Arrival.IsZero())
+		}
+	})
+
+	t.Run("OverRange", func(t *testing.T) {
+		blocks, err := NewCCFeedbackReportBlocks(7, reportTime, []PacketArrival{
+			{SequenceNumber: 1, Received: true, Arrival: reportTime.Add(-8 * time.Second)},
+			{SequenceNumber: 2, Received: true, Arrival: reportTime.Add(-time.Minute)},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, blocks, 1)
+		assert.Equal(t, []CCFeedbackMetricBlock{
+			{Received: true, ArrivalTimeOffset: 0x1FFE},
+			{Received: true, ArrivalTimeOffset: 0x1FFE},
+		}, blocks[0].MetricBlocks)
+
+		for _, arrival := range blocks[0].PacketArrivals(reportTime) {
+			assert.True(t, arrival.Received)
+			assert.True(t, arrival.Arrival.IsZero())
+		}
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		blocks, err := NewCCFeedbackReportBlocks(7, reportTime, []PacketArrival{
+			{SequenceNumber: 0, Received: true, Arrival: reportTime},
+			{SequenceNumber: MaxMetricBlocksPerReport, Received: true, Arrival: reportTime},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, blocks, 2)
+		assert.Equal(t, uint16(MaxMetricBlocksPerReport-1), blocks[1].BeginSequence)
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		received := func(seqs ...uint16) []PacketArrival {
+			arrivals := make([]PacketArrival, len(seqs))
+			for i, seq := range seqs {
+				arrivals[i] = PacketArrival{SequenceNumber: seq, Received: true, Arrival: reportTime.Add(-time.Duration(i) * unit)}
+			}
+			return arrivals
+		}
+		span := func(begin uint16, n int) []PacketArrival {
+			seqs := make([]uint16, n)
+			for i := range seqs {
+				seqs[i] = begin + uint16(i)
+			}
+			return received(seqs...)
+		}
+
+		for _, test := range []struct {
+			Name     string
+			Arrivals []PacketArrival
+			Begins   []uint16
+			Sizes    []int
+		}{
+			{Name: "across wrap", Arrivals: span(65530, 20), Begins: []uint16{65530, 0}, Sizes: []int{6, 14}},
+			{Name: "two arrivals", Arrivals: received(100, 101), Begins: []uint16{100}, Sizes: []int{2}},
+			{Name: "two arrivals before wrap", Arrivals: span(65534, 6), Begins: []uint16{65534, 0}, Sizes: []int{2, 4}},
+			{Name: "two arrivals after wrap", Arrivals: span(65530, 8), Begins: []uint16{65530, 0}, Sizes: []int{6, 2}},
+		} {
+			test := test
+			t.Run(test.Name, func(t *testing.T) {
+				blocks, err := NewCCFeedbackReportBlocks(7, reportTime, test.Arrivals)
+				assert.NoError(t, err)
+				if assert.Len(t, blocks, len(test.Begins)) {
+					for i, block := range blocks {
+						assert.Equal(t, test.Begins[i], block.BeginSequence, "block %d", i)
+						assert.Len(t, block.MetricBlocks, test.Sizes[i], "block %d", i)
+					}
+				}
+
+				report := NewCCFeedbackReport(1, 2, blocks)
+				data, err := report.Marshal()
+				assert.NoError(t, err)
+				var decoded CCFeedbackReport
+				assert.NoError(t, decoded.Unmarshal(data))
+				assert.Equal(t, *report, decoded)
+
+				// Every arrival is reported.
+				reported := map[uint16]PacketArrival{}
+				for _, block := range decoded.ReportBlocks {
+					for _, arrival := range block.PacketArrivals(reportTime) {
+						reported[arrival.SequenceNumber] = arrival
+					}
+				}
+				for _, arrival := range test.Arrivals {
+					assert.Equal(t, arrival, reported[arrival.SequenceNumber])
+				}
+			})
+		}
+	})
+
+	t.Run("SingleMetricBlock", func(t *testing.T) {
+		received := func(begin uint16, n int) []PacketArrival {
+			arrivals := make([]PacketArrival, n)
+			for i := range arrivals {
+				arrivals[i] = PacketArrival{SequenceNumber: begin + uint16(i), Received: true, Arrival: reportTime}
+			}
+			return arrivals
+		}
+
+		for _, test := range []struct {
+			Name     string
+			Arrivals []PacketArrival
+		}{
+			{Name: "single arrival", Arrivals: received(100, 1)},
+			{Name: "single arrival at 65535", Arrivals: received(65535, 1)},
+			{Name: "single arrival before wrap", Arrivals: received(65535, 5)},
+			{Name: "single arrival after wrap", Arrivals: received(65530, 7)},
+			{Name: "all sequence numbers from 65535", Arrivals: append(received(65535, 1), received(0, 65535)...)},
+		} {
+			test := test
+			t.Run(test.Name, func(t *testing.T) {
+				blocks, err := NewCCFeedbackReportBlocks(7, reportTime, test.Arrivals)
+				assert.ErrorIs(t, err, errSingleMetricBlock)
+				assert.Nil(t, blocks)
+			})
+		}
+	})
+
+	t.Run("ValidateAgainstSent", func(t *testing.T) {
+		// Only sequence number 100 was sent, so its feedback can not be
+		// sent before the next packet arrives.
+		arrivals := []PacketArrival{{SequenceNumber: 100, Received: true, Arrival: reportTime}}
+		_, err := NewCCFeedbackReportBlocks(7, reportTime, arrivals)
+		assert.ErrorIs(t, err, errSingleMetricBlock)
+
+		arrivals = append(arrivals, PacketArrival{SequenceNumber: 101, Received: true, Arrival: reportTime})
+		blocks, err := NewCCFeedbackReportBlocks(7, reportTime, arrivals)
+		assert.NoError(t, err)
+		report := NewCCFeedbackReport(1, 2, blocks)
+		assert.NoError(t, report.ValidateAgainstSent(map[uint32]uint16{7: 101}))
+	})
+
+	blocks, err = NewCCFeedbackReportBlocks(7, reportTime, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, blocks)
+}
+
+func TestNewCCFeedbackReportBlocksWithPolicy(t *testing.T) {
+	reportTime := time.Unix(100, 0)
+	arrivals := []PacketArrival{
+		{SequenceNumber: 1, Received: true, Arrival: reportTime.Add(-time.Second)},
+		// Just beyond the largest offset the 13-bit field can hold.
+		{SequenceNumber: 2, Received: true, Arrival: reportTime.Add(-0x2000 * time.Second / 1024)},
+		// Arrivals after the report time are never an overflow.
+		{SequenceNumber: 3, Received: true, Arrival: reportTime.Add(time.Millisecond)},
+	}
+
+	for _, test := range []struct {
+		Name   string
+		Policy OverflowPolicy
+		Offset uint16
+		Err    error
+	}{
+		{Name: "clamp", Policy: OverflowClamp, Offset: 0x1FFE},
+		{Name: "error", Policy: OverflowError, Err: errArrivalTimeOffset},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			blocks, err := NewCCFeedbackReportBlocksWithPolicy(7, reportTime, arrivals, test.Policy)
+			if test.Err != nil {
+				assert.ErrorIs(t, err, test.Err)
+				assert.Nil(t, blocks)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, blocks, 1)
+			assert.Equal(t, []CCFeedbackMetricBlock{
+				{Received: true, ArrivalTimeOffset: 1024},
+				{Received: true, ArrivalTimeOffset: test.Offset},
+				{Received: true, ArrivalTimeOffset: 0x1FFF},
+			}, blocks[0].MetricBlocks)
+		})
+	}
+}
+
+func TestCCFeedbackReportMarshalTo(t *testing.T) {
+	report := CCFeedbackReport{
+		SenderSSRC: 1,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{
+				MediaSSRC:     2,
+				BeginSequence: 3,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNCE, ArrivalTimeOffset: 4},
+					{Received: false},
+					{Received: true, ArrivalTimeOffset: 5},
+				},
+			},
+		},
+		ReportTimestamp: 6,
+	}
+	want, err := report.Marshal()
+	assert.NoError(t, err)
+
+	t.Run("MarshalTo", func(t *testing.T) {
+		buf := make([]byte, len(want)+4)
+		n, err := report.MarshalTo(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, len(want), n)
+		assert.Equal(t, want, buf[:n])
+
+		_, err = report.MarshalTo(buf[:len(want)-1])
+		assert.ErrorIs(t, err, errPacketTooShort)
+	})
+
+	t.Run("WriteTo", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := report.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(want)), n)
+		assert.Equal(t, want, buf.Bytes())
+	})
+}
+
+func TestCCFeedbackReportMarshalTooLarge(t *testing.T) {
+	metricBlocks := make([]CCFeedbackMetricBlock, MaxMetricBlocksPerReport)
+	var report CCFeedbackReport
+	for i := 0; report.MarshalSize() <= maxPacketLength; i++ {
+		report.ReportBlocks = append(report.ReportBlocks, CCFeedbackReportBlock{
+			MediaSSRC:    uint32(i),
+			MetricBlocks: metricBlocks,
+		})
+	}
+
+	_, err := report.Marshal()
+	assert.ErrorIs(t, err, errPacketTooLarge)
+	_, err = report.MarshalTo(make([]byte, report.MarshalSize()))
+	assert.ErrorIs(t, err, errPacketTooLarge)
+
+	// The largest size the header length can describe still marshals.
+	report.ReportBlocks = report.ReportBlocks[:len(report.ReportBlocks)-1]
+	last := maxPacketLength - report.MarshalSize() - reportsOffset
+	report.ReportBlocks = append(report.ReportBlocks, CCFeedbackReportBlock{
+		MediaSSRC:    100,
+		MetricBlocks: metricBlocks[:last/metricBlockLength],
+	})
+	assert.Equal(t, maxPacketLength, report.MarshalSize())
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0xFFFF), binary.BigEndian.Uint16(data[2:]))
+}
+
+func TestCCFeedbackReportSortBlocks(t *testing.T) {
+	mbs := func(offsets ...uint16) []CCFeedbackMetricBlock {
+		out := make([]CCFeedbackMetricBlock, len(offsets))
+		for i, offset := range offsets {
+			out[i] = CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: offset}
+		}
+		return out
+	}
+	report := CCFeedbackReport{
+		SenderSSRC: 1,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 3, BeginSequence: 0, MetricBlocks: mbs(5, 6)},
+			{MediaSSRC: 2, BeginSequence: 20, MetricBlocks: mbs(3, 4)},
+			// Continues into the block at 0.
+			{MediaSSRC: 3, BeginSequence: 65530, MetricBlocks: mbs(7, 8, 9, 10, 11, 12)},
+			{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: mbs(1, 2)},
+		},
+		ReportTimestamp: 9,
+	}
+
+	report.SortBlocks()
+	assert.NoError(t, report.ValidateRFC8888())
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x8B, 0xCD, 0x00, 0x10, // V=2, P=0, FMT=11, PT=205, Length=16
+		0x00, 0x00, 0x00, 0x01, // Sender SSRC=1
+		0x00, 0x00, 0x00, 0x02, // Media SSRC=2
+		0x00, 0x0A, 0x00, 0x01, // begin_seq=10, num_reports=1
+		0x80, 0x01, 0x80, 0x02,
+		0x00, 0x00, 0x00, 0x02, // Media SSRC=2
+		0x00, 0x14, 0x00, 0x01, // begin_seq=20, num_reports=1
+		0x80, 0x03, 0x80, 0x04,
+		0x00, 0x00, 0x00, 0x03, // Media SSRC=3
+		0xFF, 0xFA, 0x00, 0x05, // begin_seq=65530, num_reports=5
+		0x80, 0x07, 0x80, 0x08,
+		0x80, 0x09, 0x80, 0x0A,
+		0x80, 0x0B, 0x80, 0x0C,
+		0x00, 0x00, 0x00, 0x03, // Media SSRC=3
+		0x00, 0x00, 0x00, 0x01, // begin_seq=0, num_reports=1
+		0x80, 0x05, 0x80, 0x06,
+		0x00, 0x00, 0x00, 0x09, // Report Timestamp=9
+	}, data)
+
+	// Sorting again changes nothing.
+	report.SortBlocks()
+	again, err := report.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, data, again)
+
+	t.Run("without wraparound", func(t *testing.T) {
+		report := CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 60000, MetricBlocks: mbs(5, 6)},
+			{MediaSSRC: 1, BeginSequence: 0, MetricBlocks: mbs(1, 2)},
+			{MediaSSRC: 1, BeginSequence: 30000, MetricBlocks: mbs(3, 4)},
+		}}
+		report.SortBlocks()
+		assert.Equal(t, []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 0, MetricBlocks: mbs(1, 2)},
+			{MediaSSRC: 1, BeginSequence: 30000, MetricBlocks: mbs(3, 4)},
+			{MediaSSRC: 1, BeginSequence: 60000, MetricBlocks: mbs(5, 6)},
+		}, report.ReportBlocks)
+	})
+
+	t.Run("ending at 65535", func(t *testing.T) {
+		// Nothing continues at 0, so the blocks do not wrap.
+		report := CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 65534, MetricBlocks: mbs(3, 4)},
+			{MediaSSRC: 1, BeginSequence: 2, MetricBlocks: mbs(1, 2)},
+		}}
+		report.SortBlocks()
+		assert.Equal(t, uint16(2), report.ReportBlocks[0].BeginSequence)
+	})
+
+	var empty CCFeedbackReport
+	empty.SortBlocks()
+	assert.Empty(t, empty.ReportBlocks)
+}
+
+func TestCCFeedbackReportValidateAgainstSent(t *testing.T) {
+	block := func(ssrc uint32, begin uint16, n int) CCFeedbackReportBlock {
+		return CCFeedbackReportBlock{MediaSSRC: ssrc, BeginSequence: begin, MetricBlocks: make([]CCFeedbackMetricBlock, n)}
+	}
+	highestSent := map[uint32]uint16{1: 100, 2: 5}
+
+	for _, test := range []struct {
+		Name    string
+		Blocks  []CCFeedbackReportBlock
+		WantErr error
+	}{
+		{Name: "up to highest", Blocks: []CCFeedbackReportBlock{block(1, 90, 11), block(2, 0, 6)}},
+		{Name: "before highest", Blocks: []CCFeedbackReportBlock{block(1, 10, 20)}},
+		{Name: "across wrap", Blocks: []CCFeedbackReportBlock{block(2, 65530, 12)}},
+		{Name: "before wrap", Blocks: []CCFeedbackReportBlock{block(2, 65000, 100)}},
+		{Name: "empty block", Blocks: []CCFeedbackReportBlock{block(3, 0, 0)}},
+		{Name: "past highest", Blocks: []CCFeedbackReportBlock{block(1, 90, 12)}, WantErr: errSequenceNotSent},
+		{Name: "past highest after wrap", Blocks: []CCFeedbackReportBlock{block(2, 65530, 13)}, WantErr: errSequenceNotSent},
+		{Name: "all after highest", Blocks: []CCFeedbackReportBlock{block(1, 200, 2)}, WantErr: errSequenceNotSent},
+		{Name: "unknown SSRC", Blocks: []CCFeedbackReportBlock{block(1, 90, 2), block(3, 0, 2)}, WantErr: errUnknownMediaSSRC},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			report := CCFeedbackReport{ReportBlocks: test.Blocks}
+			err := report.ValidateAgainstSent(highestSent)
+			if test.WantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.WantErr)
+			}
+		})
+	}
+}
+
+func TestCCFeedbackReportECNCounts(t *testing.T) {
+	report := CCFeedbackReport{
+		ReportBlocks: []CCFeedbackReportBlock{
+			{
+				MediaSSRC: 1,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNECT0},
+					{Received: true, ECN: ECNECT0},
+					{Received: true, ECN: ECNECT1},
+				},
+			},
+			{
+				MediaSSRC: 2,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNCE},
+					{Received: false, ECN: ECNCE},
+					{Received: true, ECN: ECNNonECT},
+					{Received: false},
+				},
+			},
+		},
+	}
+
+	// Round trip so the padding of the first, odd-sized block is on the wire
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+	var decoded CCFeedbackReport
+	assert.NoError(t, decoded.UnmarshalWithOptions(data, DecodeOptions{PreserveNotReceivedBits: true}))
+
+	for _, r := range []CCFeedbackReport{report, decoded} {
+		ect0, ect1, ce, nonECT, lost := r.ECNCounts()
+		assert.Equal(t, 2, ect0)
+		assert.Equal(t, 1, ect1)
+		assert.Equal(t, 1, ce)
+		assert.Equal(t, 1, nonECT)
+		assert.Equal(t, 2, lost)
+	}
+}
+
+func TestSuggestedFeedbackInterval(t *testing.T) {
+	for _, test := range []struct {
+		RTT        time.Duration
+		NumSenders int
+		Want       time.Duration
+	}{
+		{RTT: 0, NumSenders: 1, Want: 50 * time.Millisecond},
+		{RTT: 49 * time.Millisecond, NumSenders: 1, Want: 50 * time.Millisecond},
+		{RTT: 50 * time.Millisecond, NumSenders: 1, Want: 50 * time.Millisecond},
+		{RTT: 120 * time.Millisecond, NumSenders: 1, Want: 120 * time.Millisecond},
+		{RTT: 200 * time.Millisecond, NumSenders: 1, Want: 200 * time.Millisecond},
+		{RTT: 201 * time.Millisecond, NumSenders: 1, Want: 200 * time.Millisecond},
+		{RTT: time.Second, NumSenders: 1, Want: 200 * time.Millisecond},
+		{RTT: 0, NumSenders: 0, Want: 50 * time.Millisecond},
+		{RTT: 0, NumSenders: -1, Want: 50 * time.Millisecond},
+		{RTT: 80 * time.Millisecond, NumSenders: 2, Want: 100 * time.Millisecond},
+		{RTT: 120 * time.Millisecond, NumSenders: 2, Want: 120 * time.Millisecond},
+		{RTT: 0, NumSenders: 4, Want: 200 * time.Millisecond},
+		{RTT: 0, NumSenders: 10, Want: 200 * time.Millisecond},
+		{RTT: 0, NumSenders: math.MaxInt, Want: 200 * time.Millisecond},
+	} {
+		assert.Equal(t, test.Want, SuggestedFeedbackInterval(test.RTT, test.NumSenders), "rtt %v, %d senders", test.RTT, test.NumSenders)
+	}
+}
+
+func TestECN(t *testing.T) {
+	// RFC 3168 assigns the bits counterintuitively: ECT(1) is 01 and
+	// ECT(0) is 10.
+	for _, test := range []struct {
+		ECN  ECN
+		Name string
+		Bits string
+		IP   uint8
+	}{
+		{ECN: ECNNonECT, Name: "Non-ECT", Bits: "00", IP: 0b00},
+		{ECN: ECNECT1, Name: "ECT(1)", Bits: "01", IP: 0b01},
+		{ECN: ECNECT0, Name: "ECT(0)", Bits: "10", IP: 0b10},
+		{ECN: ECNCE, Name: "CE", Bits: "11", IP: 0b11},
+	} {
+		assert.Equal(t, test.Name, test.ECN.String())
+		assert.Equal(t, test.Bits, fmt.Sprintf("%02b", uint8(test.ECN)))
+		assert.True(t, test.ECN.Valid())
+
+		assert.Equal(t, test.IP, test.ECN.ToIPECN(), test.Name)
+		assert.Equal(t, test.ECN, FromIPECN(test.IP), test.Name)
+		// A TOS octet with DSCP AF41 (34) and the ECN field.
+		assert.Equal(t, test.ECN, FromIPECN(34<<2|test.IP), test.Name)
+
+		// The metric block carries the IP field in its ECN bits.
+		data, err := CCFeedbackMetricBlock{Received: true, ECN: FromIPECN(test.IP)}.marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, test.IP, data[0]>>5&0x03, test.Name)
+
+		for _, s := range []string{test.Name, strings.ToLower(test.Name), test.Bits} {
+			e, err := ParseECN(s)
+			assert.NoError(t, err, s)
+			assert.Equal(t, test.ECN, e, s)
+		}
+	}
+
+	assert.False(t, ECN(4).Valid())
+	assert.Equal(t, "ECN(4)", ECN(4).String())
+	assert.Equal(t, uint8(0), ECN(4).ToIPECN())
+
+	for _, s := range []string{"", "ECT", "ECT(2)", "100", "2", "Non ECT"} {
+		_, err := ParseECN(s)
+		assert.ErrorIs(t, err, errInvalidECN, s)
+	}
+}
+
+func TestCCFeedbackReportRebase(t *testing.T) {
+	newReport := func() *CCFeedbackReport {
+		return NewCCFeedbackReport(1, 0x01000000, []CCFeedbackReportBlock{{
+			MediaSSRC:     2,
+			BeginSequence: 100,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 5},
+				{Received: true, ArrivalTimeOffset: 1000},
+				{Received: false},
+				{Received: true, ArrivalTimeOffset: 0x1FF9},
+				{Received: true, ArrivalTimeOffset: ArrivalTimeOffsetOverRange},
+				{Received: true, ArrivalTimeOffset: ArrivalTimeOffsetUnavailable},
+			},
+		}})
+	}
+	offsets := func(report *CCFeedbackReport) []uint16 {
+		var out []uint16
+		for _, mb := range report.ReportBlocks[0].MetricBlocks {
+			out = append(out, mb.ArrivalTimeOffset)
+		}
+		return out
+	}
+
+	// 64 report timestamp units make up one arrival time offset unit.
+	for _, test := range []struct {
+		Name      string
+		Timestamp uint32
+		Want      []uint16
+	}{
+		{
+			Name:      "unchanged",
+			Timestamp: 0x01000000,
+			Want:      []uint16{5, 1000, 0, 0x1FF9, 0x1FFE, 0x1FFF},
+		},
+		{
+			Name:      "positive shift",
+			Timestamp: 0x01000000 + 10*64,
+			Want:      []uint16{15, 1010, 0, 0x1FFF, 0x1FFE, 0x1FFF},
+		},
+		{
+			Name:      "negative shift",
+			Timestamp: 0x01000000 - 10*64,
+			Want:      []uint16{0x1FFF, 990, 0, 0x1FEF, 0x1FFF, 0x1FFF},
+		},
+		{
+			Name:      "rounds to nearest",
+			Timestamp: 0x01000000 + 2*64 + 33,
+			Want:      []uint16{8, 1003, 0, 0x1FFC, 0x1FFE, 0x1FFF},
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			report := newReport()
+			assert.NoError(t, report.Rebase(test.Timestamp))
+			assert.Equal(t, test.Timestamp, report.ReportTimestamp)
+			assert.Equal(t, test.Want, offsets(report))
+			assert.Equal(t, ECNECT0, report.ReportBlocks[0].MetricBlocks[0].ECN)
+			assert.False(t, report.ReportBlocks[0].MetricBlocks[2].Received)
+		})
+	}
+
+	t.Run("across timestamp wrap", func(t *testing.T) {
+		report := newReport()
+		report.ReportTimestamp = 0xFFFFFFC0
+		assert.NoError(t, report.Rebase(0x00000040))
+		assert.Equal(t, []uint16{7, 1002, 0, 0x1FFB, 0x1FFE, 0x1FFF}, offsets(report))
+	})
+
+	t.Run("shift too large", func(t *testing.T) {
+		for _, timestamp := range []uint32{0x01000000 + 0x1FFE*64, 0x01000000 - 0x1FFE*64} {
+			report := newReport()
+			assert.ErrorIs(t, report.Rebase(timestamp), errRebaseOutOfRange)
+			assert.Equal(t, newReport(), report)
+		}
+	})
+}
+
+func TestCCFeedbackReportStatsBySSRC(t *testing.T) {
+	report := CCFeedbackReport{
+		ReportBlocks: []CCFeedbackReportBlock{
+			{
+				MediaSSRC:     1,
+				BeginSequence: 0xFFFE,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNECT0},
+					{Received: false},
+				},
+			},
+			{
+				MediaSSRC:     2,
+				BeginSequence: 100,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNNonECT},
+					{Received: true, ECN: ECNECT1},
+					{Received: true, ECN: ECNECT1},
+				},
+			},
+			{
+				// SSRC 1 continues after the sequence number wrap.
+				MediaSSRC:     1,
+				BeginSequence: 0,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNCE},
+					{Received: true, ECN: ECNECT0},
+					{Received: false},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, map[uint32]StreamFeedbackStats{
+		1: {Received: 3, Lost: 2, ECT0: 2, CE: 1},
+		2: {Received: 3, NonECT: 1, ECT1: 2},
+	}, report.StatsBySSRC())
+
+	assert.Equal(t, map[uint32]StreamFeedbackStats{}, (&CCFeedbackReport{}).StatsBySSRC())
+}
+
+func TestDiffFeedback(t *testing.T) {
+	report := func(blocks ...CCFeedbackReportBlock) *CCFeedbackReport {
+		return &CCFeedbackReport{ReportBlocks: blocks}
+	}
+	block := func(ssrc uint32, begin uint16, received ...bool) CCFeedbackReportBlock {
+		b := CCFeedbackReportBlock{MediaSSRC: ssrc, BeginSequence: begin}
+		for _, r := range received {
+			b.MetricBlocks = append(b.MetricBlocks, CCFeedbackMetricBlock{Received: r})
+		}
+		return b
+	}
+
+	for _, test := range []struct {
+		Name          string
+		Prev, Curr    *CCFeedbackReport
+		NewlyLost     []uint16
+		NewlyReceived []uint16
+	}{
+		{
+			Name:      "received then lost",
+			Prev:      report(block(1, 10, true, true, true)),
+			Curr:      report(block(1, 10, true, false, true)),
+			NewlyLost: []uint16{11},
+		},
+		{
+			Name:          "lost then received",
+			Prev:          report(block(1, 10, false, true)),
+			Curr:          report(block(1, 10, true, true)),
+			NewlyReceived: []uint16{10},
+		},
+		{
+			Name:          "partial overlap",
+			Prev:          report(block(1, 10, true, false, true, true)),
+			Curr:          report(block(1, 12, false, true, false, false)),
+			NewlyLost:     []uint16{12},
+			NewlyReceived: nil,
+		},
+		{
+			Name:          "sequence wrap",
+			Prev:          report(block(1, 0xFFFE, true, false), block(1, 0, true, true)),
+			Curr:          report(block(1, 0xFFFF, true, false, false)),
+			NewlyLost:     []uint16{0, 1},
+			NewlyReceived: []uint16{0xFFFF},
+		},
+		{
+			Name: "no overlap",
+			Prev: report(block(1, 10, true, true)),
+			Curr: report(block(1, 20, false, false)),
+		},
+		{
+			Name: "other ssrc",
+			Prev: report(block(2, 10, true, true)),
+			Curr: report(block(2, 10, false, false), block(1, 10, false)),
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			newlyLost, newlyReceived := DiffFeedback(test.Prev, test.Curr, 1)
+			assert.Equal(t, test.NewlyLost, newlyLost)
+			assert.Equal(t, test.NewlyReceived, newlyReceived)
+		})
+	}
+}
+
+func TestArrivalDeltas(t *testing.T) {
+	rx := func(offset uint16) CCFeedbackMetricBlock {
+		return CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: offset}
+	}
+	lost := CCFeedbackMetricBlock{}
+
+	for _, test := range []struct {
+		Name   string
+		Blocks []CCFeedbackReportBlock
+		Want   []time.Duration
+	}{
+		{
+			Name: "clean run",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{rx(1024), rx(1004), rx(984), rx(0)}},
+			},
+			Want: []time.Duration{
+				20 * time.Second / 1024,
+				20 * time.Second / 1024,
+				984 * time.Second / 1024,
+			},
+		},
+		{
+			Name: "lost packet",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{rx(100), lost, rx(60), rx(40)}},
+			},
+			Want: []time.Duration{
+				40 * time.Second / 1024,
+				20 * time.Second / 1024,
+			},
+		},
+		{
+			Name: "no arrival time",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{rx(100), rx(0x1FFF), rx(60), rx(0x1FFE), rx(40), rx(30)}},
+			},
+			Want: []time.Duration{10 * time.Second / 1024},
+		},
+		{
+			Name: "reordered",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{rx(50), rx(60)}},
+			},
+			Want: []time.Duration{-10 * time.Second / 1024},
+		},
+		{
+			Name: "other SSRCs and blocks",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{rx(100), rx(90)}},
+				{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{rx(500), rx(400)}},
+				{MediaSSRC: 1, BeginSequence: 20, MetricBlocks: []CCFeedbackMetricBlock{rx(70), lost}},
+			},
+			Want: []time.Duration{
+				10 * time.Second / 1024,
+				20 * time.Second / 1024,
+			},
+		},
+		{
+			Name: "single arrival",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{rx(100), lost}},
+			},
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			report := &CCFeedbackReport{ReportBlocks: test.Blocks}
+			assert.Equal(t, test.Want, ArrivalDeltas(report, 1))
+		})
+	}
+}
+
+func TestCCFeedbackReportMarshalJSON(t *testing.T) {
+	report := NewCCFeedbackReport(1, 2, []CCFeedbackReportBlock{{
+		MediaSSRC:     3,
+		BeginSequence: 0xFFFF,
+		MetricBlocks: []CCFeedbackMetricBlock{
+			{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 512},
+			{Received: false},
+			{Received: true, ECN: ECNCE, ArrivalTimeOffset: ArrivalTimeOffsetUnavailable},
+		},
+	}})
+
+	data, err := json.Marshal(report)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"senderSsrc": 1,
+		"reportTimestamp": 2,
+		"reportBlocks": [{
+			"mediaSsrc": 3,
+			"beginSequence": 65535,
+			"metricBlocks": [
+				{"sequence": 65535, "received": true, "ecn": "ECT(0)", "arrivalTimeOffsetMs": 500},
+				{"sequence": 0, "received": false},
+				{"sequence": 1, "received": true, "ecn": "CE"}
+			]
+		}]
+	}`, string(data))
+
+	data, err = json.Marshal(CCFeedbackMetricBlock{Received: true, ECN: ECNNonECT, ArrivalTimeOffset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"received":true,"ecn":"Non-ECT","arrivalTimeOffsetMs":0.9765625}`, string(data))
+
+	data, err = json.Marshal(CCFeedbackReport{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"senderSsrc":0,"reportTimestamp":0,"reportBlocks":[]}`, string(data))
+}
+
+func TestQuantizeArrival(t *testing.T) {
+	for _, test := range []struct {
+		D        time.Duration
+		Offset   uint16
+		Residual time.Duration
+		OK       bool
+	}{
+		{D: 0, Offset: 0, Residual: 0, OK: true},
+		{D: 400 * time.Microsecond, Offset: 0, Residual: 400 * time.Microsecond, OK: true},
+		// 1/1024 seconds are 976562.5ns, which ArrivalDelay truncates.
+		{D: 500 * time.Microsecond, Offset: 1, Residual: -476562, OK: true},
+		{D: time.Millisecond, Offset: 1, Residual: 23438, OK: true},
+		{D: 1500 * time.Microsecond, Offset: 2, Residual: -453125, OK: true},
+		{D: 8189 * time.Second / 1024, Offset: 0x1FFD, Residual: 0, OK: true},
+		{D: 8189*time.Second/1024 + 488281, Offset: 0x1FFD, Residual: 488281, OK: true},
+		{D: 8189*time.Second/1024 + 488282, Offset: 0x1FFE},
+		{D: time.Minute, Offset: 0x1FFE},
+		{D: -time.Microsecond, Offset: 0x1FFF},
+	} {
+		offset, residual, ok := QuantizeArrival(test.D)
+		assert.Equal(t, test.Offset, offset, test.D)
+		assert.Equal(t, test.Residual, residual, test.D)
+		assert.Equal(t, test.OK, ok, test.D)
+	}
+
+	// The residual completes the quantized delay and never exceeds half a
+	// unit.
+	for d := time.Duration(0); d < 10*time.Millisecond; d += 7919 * time.Nanosecond {
+		offset, residual, ok := QuantizeArrival(d)
+		assert.True(t, ok)
+		assert.Equal(t, d, CCFeedbackMetricBlock{ArrivalTimeOffset: offset}.ArrivalDelay()+residual)
+		assert.LessOrEqual(t, residual, 488282*time.Nanosecond)
+		assert.GreaterOrEqual(t, residual, -488282*time.Nanosecond)
+	}
+}
+
+func TestCCFeedbackMetricBlockHasArrivalTime(t *testing.T) {
+	for _, test := range []struct {
+		Name  string
+		Block CCFeedbackMetricBlock
+		Data  []byte
+		Want  bool
+	}{
+		{
+			Name:  "received",
+			Block: CCFeedbackMetricBlock{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 0x1FFD},
+			Data:  []byte{0xDF, 0xFD},
+			Want:  true,
+		},
+		{
+			Name:  "over-range",
+			Block: CCFeedbackMetricBlock{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 0x1FFE},
+			Data:  []byte{0xDF, 0xFE},
+		},
+		{
+			Name:  "unavailable",
+			Block: CCFeedbackMetricBlock{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 0x1FFF},
+			Data:  []byte{0xDF, 0xFF},
+		},
+		{
+			Name:  "not received",
+			Block: CCFeedbackMetricBlock{},
+			Data:  []byte{0x00, 0x00},
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Want, test.Block.HasArrivalTime())
+
+			data, err := test.Block.marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, test.Data, data)
+
+			var decoded CCFeedbackMetricBlock
+			assert.NoError(t, decoded.unmarshal(data))
+			assert.Equal(t, test.Block, decoded)
+			assert.Equal(t, test.Want, decoded.HasArrivalTime())
+		})
+	}
+
+	t.Run("constructed without arrival time", func(t *testing.T) {
+		blocks, err := NewCCFeedbackReportBlocks(1, time.Unix(10, 0), []PacketArrival{
+			{SequenceNumber: 1, Received: true, Arrival: time.Unix(9, 0)},
+			{SequenceNumber: 2, Received: true},
+		})
+		assert.NoError(t, err)
+		assert.True(t, blocks[0].MetricBlocks[0].HasArrivalTime())
+		assert.False(t, blocks[0].MetricBlocks[1].HasArrivalTime())
+		assert.Equal(t, uint16(0x1FFF), blocks[0].MetricBlocks[1].ArrivalTimeOffset)
+	})
+}
+
+// inconsistentReportBlock encodes to a different size than it claims.
+type inconsistentReportBlock struct {
+	data   []byte
+	length int
+}
+
+func (b inconsistentReportBlock) marshal() ([]byte, error) { return b.data, nil }
+func (b inconsistentReportBlock) len() int                 { return b.length }
+
+func TestMarshalReportBlockTo(t *testing.T) {
+	buf := make([]byte, 16)
+
+	n, err := marshalReportBlockTo(buf, inconsistentReportBlock{data: []byte{1, 2, 3, 4, 5, 6, 7, 8}, length: 8})
+	assert.NoError(t, err)
+	assert.Equal(t, 8, n)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, buf[:8])
+
+	for name, block := range map[string]inconsistentReportBlock{
+		"longer than len":  {data: make([]byte, 12), length: 8},
+		"shorter than len": {data: make([]byte, 8), length: 12},
+		"exceeds buffer":   {data: make([]byte, 20), length: 20},
+	} {
+		_, err := marshalReportBlockTo(buf, block)
+		assert.ErrorIs(t, err, errWrongMarshalSize, name)
+	}
+
+	// The real report block always agrees with itself.
+	block := CCFeedbackReportBlock{
+		MediaSSRC:     1,
+		BeginSequence: 2,
+		MetricBlocks:  []CCFeedbackMetricBlock{{Received: true}, {Received: false}, {Received: true}},
+	}
+	n, err = marshalReportBlockTo(buf, &block)
+	assert.NoError(t, err)
+	assert.Equal(t, block.len(), n)
+}
+
+func TestMarshalReportToAlignment(t *testing.T) {
+	marshal := func(blocks ...reportBlockMarshaler) ([]byte, error) {
+		buf := make([]byte, 64)
+		n, err := marshalReportTo(buf, 1, 2, len(blocks), func(i int) reportBlockMarshaler { return blocks[i] })
+		return buf[:n], err
+	}
+
+	// Three metric blocks are encoded with two bytes of padding.
+	block := CCFeedbackReportBlock{MediaSSRC: 1, MetricBlocks: make([]CCFeedbackMetricBlock, 3)}
+	data, err := marshal(&block)
+	assert.NoError(t, err)
+	want, err := CCFeedbackReport{SenderSSRC: 1, ReportTimestamp: 2, ReportBlocks: []CCFeedbackReportBlock{block}}.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, want, data)
+
+	// A report block whose length forgets to round its three metric blocks
+	// up to a 32-bit word leaves the report misaligned.
+	broken := inconsistentReportBlock{data: make([]byte, reportsOffset+2*3), length: reportsOffset + 2*3}
+	_, err = marshal(&block, broken)
+	assert.ErrorIs(t, err, errReportMisaligned)
+}
+
+func TestCCFeedbackReportUnmarshalBlockLengthMismatch(t *testing.T) {
+	// The sender left out the metric block aligning the odd-sized report
+	// block, and padded the packet by two octets instead.
+	data := []byte{
+		0xAB, 0xCD, 0x00, 0x06, // V=2, P=1, FMT=11, PT=205, Length=6
+		0x01, 0x02, 0x03, 0x04, // Sender SSRC
+		0x11, 0x12, 0x13, 0x14, // Media SSRC
+		0x00, 0x10, 0x00, 0x02, // Begin Sequence, Num Reports=3
+		0x80, 0x01, 0x80, 0x02, // Metric Blocks
+		0x80, 0x03, 0x05, 0x06, // Metric Block, Report Timestamp
+		0x07, 0x08, 0x00, 0x02, // Report Timestamp, Padding
+	}
+
+	var report CCFeedbackReport
+	err := report.Unmarshal(data)
+	assert.ErrorIs(t, err, errBlockLengthMismatch)
+	assert.EqualError(t, err, "report block 0 at offset 8: feedback report block length does not match num_reports")
+
+	// With the aligning metric block, the same report decodes.
+	aligned := []byte{
+		0x8B, 0xCD, 0x00, 0x06, // V=2, P=0, FMT=11, PT=205, Length=6
+		0x01, 0x02, 0x03, 0x04, // Sender SSRC
+		0x11, 0x12, 0x13, 0x14, // Media SSRC
+		0x00, 0x10, 0x00, 0x02, // Begin Sequence, Num Reports=3
+		0x80, 0x01, 0x80, 0x02, // Metric Blocks
+		0x80, 0x03, 0x00, 0x00, // Metric Block, padding
+		0x05, 0x06, 0x07, 0x08, // Report Timestamp
+	}
+	assert.NoError(t, report.Unmarshal(aligned))
+	assert.Len(t, report.ReportBlocks[0].MetricBlocks, 3)
+	assert.Equal(t, uint32(0x05060708), report.ReportTimestamp)
+}
+
+func TestCCFeedbackReportUnmarshalErrorOffset(t *testing.T) {
+	data := []byte{
+		0x8B, 0xCD, 0x00, 0x06, // V=2, P=0, FMT=11, PT=205, Length=6
+		0x01, 0x02, 0x03, 0x04, // Sender SSRC
+		0x11, 0x12, 0x13, 0x14, // Media SSRC
+		0x00, 0x10, 0x00, 0x01, // Begin Sequence, Num Reports
+		0x80, 0x01, 0x80, 0x02, // Metric Blocks
+		0x21, 0x22, 0x23, 0x24, // Truncated second report block
+		0x05, 0x06, 0x07, 0x08, // Report Timestamp
+	}
+
+	var report CCFeedbackReport
+	err := report.Unmarshal(data)
+	assert.ErrorIs(t, err, errReportBlockLength)
+	assert.EqualError(t, err, "report block 1 at offset 20: feedback report blocks must be at least 8 bytes")
+}
+
+func TestNewReportBlockFromBitmap(t *testing.T) {
+	block, err := NewReportBlockFromBitmap(2, 65534,
+		[]bool{true, false, true, true},
+		[]ECN{ECNECT0, ECNCE, ECNCE, ECNNonECT},
+		[]uint16{30, 0x1FFF, 20, 0x1FFF},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, CCFeedbackReportBlock{
+		MediaSSRC:     2,
+		BeginSequence: 65534,
+		MetricBlocks: []CCFeedbackMetricBlock{
+			{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 30},
+			// Not received, so the ECN marking and offset are dropped.
+			{},
+			{Received: true, ECN: ECNCE, ArrivalTimeOffset: 20},
+			{Received: true, ECN: ECNNonECT, ArrivalTimeOffset: 0x1FFF},
+		},
+	}, block)
+
+	for _, test := range []struct {
+		Name     string
+		Received []bool
+		ECN      []ECN
+		Offsets  []uint16
+		WantErr  error
+	}{
+		{Name: "short ECN", Received: []bool{true, true}, ECN: []ECN{ECNECT0}, Offsets: []uint16{1, 2}, WantErr: errBitmapLengthMismatch},
+		{Name: "short offsets", Received: []bool{true, true}, ECN: []ECN{ECNECT0, ECNECT0}, Offsets: []uint16{1}, WantErr: errBitmapLengthMismatch},
+		{Name: "long offsets", Received: []bool{true}, ECN: []ECN{ECNECT0}, Offsets: []uint16{1, 2}, WantErr: errBitmapLengthMismatch},
+		{
+			Name:     "too long",
+			Received: make([]bool, MaxMetricBlocksPerReport+1),
+			ECN:      make([]ECN, MaxMetricBlocksPerReport+1),
+			Offsets:  make([]uint16, MaxMetricBlocksPerReport+1),
+			WantErr:  errTooManyReports,
+		},
+		{Name: "invalid ECN", Received: []bool{true, true}, ECN: []ECN{ECNECT0, 4}, Offsets: []uint16{1, 2}, WantErr: errInvalidECN},
+		{Name: "offset out of range", Received: []bool{true, true}, ECN: []ECN{ECNECT0, ECNECT0}, Offsets: []uint16{0x2000, 2}, WantErr: errArrivalTimeOffset},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewReportBlockFromBitmap(2, 0, test.Received, test.ECN, test.Offsets)
+			assert.ErrorIs(t, err, test.WantErr)
+		})
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		block, err := NewReportBlockFromBitmap(2, 0,
+			make([]bool, MaxMetricBlocksPerReport),
+			make([]ECN, MaxMetricBlocksPerReport),
+			make([]uint16, MaxMetricBlocksPerReport),
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, block.Validate())
+	})
+}
+
+func TestCCFeedbackReportBlockAddArrival(t *testing.T) {
+	received := func(offset uint16) CCFeedbackMetricBlock {
+		return CCFeedbackMetricBlock{Received: true, ECN: ECNECT0, ArrivalTimeOffset: offset}
+	}
+	lost := CCFeedbackMetricBlock{}
+
+	t.Run("in order", func(t *testing.T) {
+		block := CCFeedbackReportBlock{MediaSSRC: 1}
+		for i := uint16(0); i < 3; i++ {
+			assert.NoError(t, block.AddArrival(0xFFFF+i, received(i)))
+		}
+		assert.Equal(t, CCFeedbackReportBlock{
+			MediaSSRC:     1,
+			BeginSequence: 0xFFFF,
+			MetricBlocks:  []CCFeedbackMetricBlock{received(0), received(1), received(2)},
+		}, block)
+	})
+
+	t.Run("gap", func(t *testing.T) {
+		block := CCFeedbackReportBlock{}
+		assert.NoError(t, block.AddArrival(10, received(1)))
+		assert.NoError(t, block.AddArrival(13, received(2)))
+		assert.Equal(t, uint16(10), block.BeginSequence)
+		assert.Equal(t, []CCFeedbackMetricBlock{received(1), lost, lost, received(2)}, block.MetricBlocks)
+
+		// A late arrival fills the gap.
+		assert.NoError(t, block.AddArrival(11, received(3)))
+		assert.Equal(t, []CCFeedbackMetricBlock{received(1), received(3), lost, received(2)}, block.MetricBlocks)
+	})
+
+	t.Run("before begin sequence", func(t *testing.T) {
+		block := CCFeedbackReportBlock{}
+		assert.NoError(t, block.AddArrival(1, received(1)))
+		assert.NoError(t, block.AddArrival(0xFFFE, received(2)))
+		assert.Equal(t, uint16(0xFFFE), block.BeginSequence)
+		assert.Equal(t, []CCFeedbackMetricBlock{received(2), lost, lost, received(1)}, block.MetricBlocks)
+	})
+
+	t.Run("too many metric blocks", func(t *testing.T) {
+		block := CCFeedbackReportBlock{}
+		assert.NoError(t, block.AddArrival(1000, received(1)))
+		assert.NoError(t, block.AddArrival(1000+MaxMetricBlocksPerReport-1, received(2)))
+		assert.Len(t, block.MetricBlocks, MaxMetricBlocksPerReport)
+		assert.ErrorIs(t, block.AddArrival(1000+MaxMetricBlocksPerReport, received(3)), errTooManyReports)
+		assert.ErrorIs(t, block.AddArrival(999, received(3)), errTooManyReports)
+		assert.Len(t, block.MetricBlocks, MaxMetricBlocksPerReport)
+		assert.Equal(t, uint16(1000), block.BeginSequence)
+	})
+}
+
+func TestCCFeedbackReportBlockValidate(t *testing.T) {
+	for _, test := range []struct {
+		Name          string
+		BeginSequence uint16
+		MetricBlocks  int
+		Err           error
+	}{
+		{Name: "empty", BeginSequence: 0xFFFF, MetricBlocks: 0},
+		{Name: "single", BeginSequence: 10, MetricBlocks: 1, Err: errSingleMetricBlock},
+		{Name: "valid", BeginSequence: 10, MetricBlocks: 2},
+		{Name: "maximum", BeginSequence: 0, MetricBlocks: MaxMetricBlocksPerReport},
+		{Name: "too many", BeginSequence: 0, MetricBlocks: MaxMetricBlocksPerReport + 1, Err: errTooManyReports},
+		{Name: "ends at 65535", BeginSequence: 0xFFFE, MetricBlocks: 2},
+		{Name: "wraps", BeginSequence: 0xFFFE, MetricBlocks: 3, Err: errSequenceWrap},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			block := CCFeedbackReportBlock{
+				BeginSequence: test.BeginSequence,
+				MetricBlocks:  make([]CCFeedbackMetricBlock, test.MetricBlocks),
+			}
+			assert.ErrorIs(t, block.Validate(), test.Err)
+
+			// Valid blocks survive a round trip.
+			if test.Err != nil {
+				return
+			}
+			report := CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{block}}
+			data, err := report.Marshal()
+			assert.NoError(t, err)
+			var decoded CCFeedbackReport
+			assert.NoError(t, decoded.Unmarshal(data))
+			assert.Len(t, decoded.ReportBlocks[0].MetricBlocks, test.MetricBlocks)
+		})
+	}
+}
+
+func TestCCFeedbackToTransportLayerCC(t *testing.T) {
+	t.Run("received and lost", func(t *testing.T) {
+		// The report timestamp is one second, so offsets of 1024 and 512
+		// put the arrivals at zero and half a second.
+		report := &CCFeedbackReport{
+			SenderSSRC:      1,
+			ReportTimestamp: 1 << 16,
+			ReportBlocks: []CCFeedbackReportBlock{
+				{
+					MediaSSRC:     2,
+					BeginSequence: 100,
+					MetricBlocks: []CCFeedbackMetricBlock{
+						{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 1024},
+						{Received: false},
+					},
+				},
+				{
+					MediaSSRC:     2,
+					BeginSequence: 103,
+					MetricBlocks: []CCFeedbackMetricBlock{
+						{Received: true, ArrivalTimeOffset: 512},
+						{Received: true, ArrivalTimeOffset: 512},
+					},
+				},
+			},
+		}
+		tlcc, err := CCFeedbackToTransportLayerCC(report)
+		assert.NoError(t, err)
+		assert.Equal(t, &TransportLayerCC{
+			Header: Header{
+				Count:  FormatTCC,
+				Type:   TypeTransportSpecificFeedback,
+				Length: 7,
+			},
+			SenderSSRC:         1,
+			MediaSSRC:          2,
+			BaseSequenceNumber: 100,
+			PacketStatusCount:  5,
+			ReferenceTime:      0,
+			PacketChunks: []PacketStatusChunk{
+				&RunLengthChunk{PacketStatusSymbol: TypeTCCPacketReceivedSmallDelta, RunLength: 1},
+				&RunLengthChunk{PacketStatusSymbol: TypeTCCPacketNotReceived, RunLength: 2},
+				&RunLengthChunk{PacketStatusSymbol: TypeTCCPacketReceivedLargeDelta, RunLength: 1},
+				&RunLengthChunk{PacketStatusSymbol: TypeTCCPacketReceivedSmallDelta, RunLength: 1},
+			},
+			RecvDeltas: []*RecvDelta{
+				{Type: TypeTCCPacketReceivedSmallDelta, Delta: 0},
+				{Type: TypeTCCPacketReceivedLargeDelta, Delta: 500000},
+				{Type: TypeTCCPacketReceivedSmallDelta, Delta: 0},
+			},
+		}, tlcc)
+
+		data, err := tlcc.Marshal()
+		assert.NoError(t, err)
+		var decoded TransportLayerCC
+		assert.NoError(t, decoded.Unmarshal(data))
+		assert.Equal(t, tlcc, &decoded)
+	})
+
+	t.Run("multiple media SSRCs", func(t *testing.T) {
+		report := &CCFeedbackReport{
+			ReportBlocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{{}, {}}},
+				{MediaSSRC: 2, MetricBlocks: []CCFeedbackMetricBlock{{}, {}}},
+			},
+		}
+		_, err := CCFeedbackToTransportLayerCC(report)
+		assert.ErrorIs(t, err, errMultipleMediaSSRCs)
+	})
+
+	t.Run("missing arrival time", func(t *testing.T) {
+		report := &CCFeedbackReport{
+			ReportBlocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ArrivalTimeOffset: ArrivalTimeOffsetUnavailable},
+					{},
+				}},
+			},
+		}
+		_, err := CCFeedbackToTransportLayerCC(report)
+		assert.ErrorIs(t, err, errMissingArrivalTime)
+	})
+}
+
+func TestCCFeedbackReportAllowLengthMismatch(t *testing.T) {
+	report := CCFeedbackReport{
+		SenderSSRC:      1,
+		ReportTimestamp: 0xAABBCCDD,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{{Received: true}, {}}},
+			{MediaSSRC: 3, BeginSequence: 20, MetricBlocks: []CCFeedbackMetricBlock{{}, {Received: true}}},
+		},
+	}
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+	// header, sender SSRC, two blocks of 12 bytes and the report timestamp
+	assert.Len(t, data, 36)
+
+	for _, test := range []struct {
+		Name      string
+		Length    uint16
+		Blocks    []CCFeedbackReportBlock
+		StrictErr error
+	}{
+		{
+			// Only the first report block fits into the announced length.
+			// Strict decoding ignores the bytes beyond it.
+			Name:   "too small",
+			Length: 5,
+			Blocks: report.ReportBlocks[:1],
+		},
+		{
+			Name:      "too large",
+			Length:    12,
+			Blocks:    report.ReportBlocks,
+			StrictErr: errLengthMismatch,
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			raw := append([]byte{}, data...)
+			binary.BigEndian.PutUint16(raw[2:], test.Length)
+
+			var strict CCFeedbackReport
+			n, err := strict.UnmarshalPrefix(raw, DecodeOptions{})
+			assert.ErrorIs(t, err, test.StrictErr)
+			if test.StrictErr == nil {
+				assert.Equal(t, (int(test.Length)+1)*4, n)
+			}
+
+			var decoded CCFeedbackReport
+			err = decoded.UnmarshalWithOptions(raw, DecodeOptions{AllowLengthMismatch: true})
+			var mismatch *LengthMismatchError
+			assert.ErrorAs(t, err, &mismatch)
+			assert.ErrorIs(t, err, errLengthMismatch)
+			assert.Equal(t, (int(test.Length)+1)*4, mismatch.HeaderLength)
+			assert.Equal(t, len(raw), mismatch.PacketLength)
+			assert.Equal(t, report.SenderSSRC, decoded.SenderSSRC)
+			assert.Equal(t, report.ReportTimestamp, decoded.ReportTimestamp)
+			assert.Equal(t, test.Blocks, decoded.ReportBlocks)
+		})
+	}
+
+	// Matching lengths decode without a warning.
+	var decoded CCFeedbackReport
+	assert.NoError(t, decoded.UnmarshalWithOptions(data, DecodeOptions{AllowLengthMismatch: true}))
+}
+
+func TestCCFeedbackReportBlockSequenceRange(t *testing.T) {
+	for _, test := range []struct {
+		Name          string
+		BeginSequence uint16
+		MetricBlocks  int
+		End           uint16
+		Wrapped       bool
+	}{
+		{Name: "empty", BeginSequence: 10, MetricBlocks: 0, End: 10},
+		{Name: "simple", BeginSequence: 10, MetricBlocks: 4, End: 14},
+		{Name: "ends at 65535", BeginSequence: 0xFFFE, MetricBlocks: 2, End: 0},
+		{Name: "wraps", BeginSequence: 0xFFFE, MetricBlocks: 4, End: 2, Wrapped: true},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			block := CCFeedbackReportBlock{
+				BeginSequence: test.BeginSequence,
+				MetricBlocks:  make([]CCFeedbackMetricBlock, test.MetricBlocks),
+			}
+			begin, end, wrapped := block.SequenceRange()
+			assert.Equal(t, test.BeginSequence, begin)
+			assert.Equal(t, test.End, end)
+			assert.Equal(t, test.Wrapped, wrapped)
+		})
+	}
+}
+
+func TestCCFeedbackReportUnmarshalLimits(t *testing.T) {
+	t.Run("bounded allocation", func(t *testing.T) {
+		// A short packet claiming 16384 metric blocks must be rejected
+		// before memory for them is allocated.
+		data := []byte{
+			0x8B, 0xCD, 0x00, 0x04, // V=2, FMT=11, PT=205, Length=4
+			0, 0, 0, 1, // sender SSRC
+			0, 0, 0, 2, 0, 0, 0x3F, 0xFF, // media SSRC, begin_seq, num_reports
+			0, 0, 0, 0, // report timestamp
+		}
+		var report CCFeedbackReport
+		var err error
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		for i := 0; i < 100; i++ {
+			err = report.Unmarshal(data)
+		}
+		runtime.ReadMemStats(&after)
+		assert.ErrorIs(t, err, errIncorrectNumReports)
+		// Decoding the metric blocks would allocate 64KiB on every call.
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<16))
+	})
+
+	t.Run("report blocks", func(t *testing.T) {
+		opts := DecodeOptions{MaxReportBlocks: 1}
+
+		report := CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{{MediaSSRC: 1}, {MediaSSRC: 2}}}
+		data, err := report.Marshal()
+		assert.NoError(t, err)
+		var decoded CCFeedbackReport
+		assert.ErrorIs(t, decoded.UnmarshalWithOptions(data, opts), errTooManyReportBlocks)
+		assert.NoError(t, decoded.Unmarshal(data))
+
+		report.ReportBlocks = report.ReportBlocks[:1]
+		data, err = report.Marshal()
+		assert.NoError(t, err)
+		assert.NoError(t, decoded.UnmarshalWithOptions(data, opts))
+	})
+
+	t.Run("report blocks default", func(t *testing.T) {
+		// The largest report holds nothing but empty report blocks.
+		report := CCFeedbackReport{ReportBlocks: make([]CCFeedbackReportBlock, maxReportBlocksPerReport)}
+		data, err := report.Marshal()
+		assert.NoError(t, err)
+		assert.Greater(t, len(data)+reportsOffset, maxPacketLength)
+		var decoded CCFeedbackReport
+		assert.NoError(t, decoded.Unmarshal(data))
+		assert.Len(t, decoded.ReportBlocks, maxReportBlocksPerReport)
+	})
+
+	t.Run("packet size", func(t *testing.T) {
+		report := CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{{MediaSSRC: 1}}}
+		data, err := report.Marshal()
+		assert.NoError(t, err)
+		var decoded CCFeedbackReport
+		assert.ErrorIs(t, decoded.UnmarshalWithOptions(data, DecodeOptions{MaxPacketSize: len(data) - 1}), errPacketTooLarge)
+		assert.NoError(t, decoded.UnmarshalWithOptions(data, DecodeOptions{MaxPacketSize: len(data)}))
+	})
+}
+
+func TestCCFeedbackReportUnmarshalTrailingBytes(t *testing.T) {
+	report := CCFeedbackReport{
+		SenderSSRC:      1,
+		ReportTimestamp: 0x01020304,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNECT1, ArrivalTimeOffset: 3},
+				{},
+				{Received: true, ArrivalTimeOffset: 1},
+			}},
+		},
+	}
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+	raw := append(append([]byte{}, data...), 0xDE, 0xAD, 0xBE, 0xEF)
+
+	var decoded CCFeedbackReport
+	n, err := decoded.UnmarshalPrefix(raw, DecodeOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, report, decoded)
+
+	decoded = CCFeedbackReport{}
+	assert.NoError(t, decoded.Unmarshal(raw))
+	assert.Equal(t, report, decoded)
+}
+
+func TestCCFeedbackReportUnmarshalFrom(t *testing.T) {
+	reports := []CCFeedbackReport{
+		{
+			SenderSSRC: 1,
+			ReportBlocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNECT1, ArrivalTimeOffset: 3},
+					{},
+					{Received: true, ArrivalTimeOffset: 1},
+				}},
+			},
+			ReportTimestamp: 0x01020304,
+		},
+		{
+			SenderSSRC:      1,
+			ReportBlocks:    []CCFeedbackReportBlock{},
+			ReportTimestamp: 0x01020305,
+		},
+	}
+	var raw []byte
+	for _, report := range reports {
+		data, err := report.Marshal()
+		assert.NoError(t, err)
+		raw = append(raw, data...)
+	}
+
+	var decoded []CCFeedbackReport
+	for len(raw) > 0 {
+		var report CCFeedbackReport
+		consumed, err := report.UnmarshalFrom(raw)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, report.MarshalSize(), consumed)
+		decoded = append(decoded, report)
+		raw = raw[consumed:]
+	}
+	assert.Equal(t, reports, decoded)
+
+	var report CCFeedbackReport
+	consumed, err := report.UnmarshalFrom([]byte{0x8B, 0xCD, 0x00, 0x02})
+	assert.ErrorIs(t, err, errPacketTooShort)
+	assert.Zero(t, consumed)
+}
+
+func TestCCFeedbackReportLossRate(t *testing.T) {
+	received := CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: 1}
+	lost := CCFeedbackMetricBlock{}
+
+	for _, test := range []struct {
+		Name   string
+		Blocks []CCFeedbackReportBlock
+		Rate   float64
+		BySSRC map[uint32]float64
+	}{
+		{
+			Name:   "no blocks",
+			BySSRC: map[uint32]float64{},
+		},
+		{
+			Name:   "empty block",
+			Blocks: []CCFeedbackReportBlock{{MediaSSRC: 1}},
+			BySSRC: map[uint32]float64{1: 0},
+		},
+		{
+			Name: "all received",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{received, received}},
+				{MediaSSRC: 2, MetricBlocks: []CCFeedbackMetricBlock{received, received, received}},
+			},
+			BySSRC: map[uint32]float64{1: 0, 2: 0},
+		},
+		{
+			Name: "all lost",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{lost, lost, lost}},
+			},
+			Rate:   1,
+			BySSRC: map[uint32]float64{1: 1},
+		},
+		{
+			// The padding of the odd-sized blocks is not counted.
+			Name: "mixed",
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{received, lost, received}},
+				{MediaSSRC: 2, MetricBlocks: []CCFeedbackMetricBlock{lost, lost, received, lost}},
+				{MediaSSRC: 1, BeginSequence: 3, MetricBlocks: []CCFeedbackMetricBlock{lost, received, lost}},
+			},
+			Rate:   0.6,
+			BySSRC: map[uint32]float64{1: 0.5, 2: 0.75},
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			report := CCFeedbackReport{ReportBlocks: test.Blocks}
+			assert.Equal(t, test.Rate, report.LossRate())
+			assert.Equal(t, test.BySSRC, report.LossRateBySSRC())
+			if len(test.Blocks) == 0 {
+				return
+			}
+
+			// The rates survive a round trip through the wire format.
+			data, err := report.Marshal()
+			assert.NoError(t, err)
+			var decoded CCFeedbackReport
+			assert.NoError(t, decoded.Unmarshal(data))
+			assert.Equal(t, test.Rate, decoded.LossRate())
+		})
+	}
+}
+
+func TestCCFeedbackReportCEMarks(t *testing.T) {
+	report := CCFeedbackReport{
+		ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNCE},
+				{Received: true, ECN: ECNECT1},
+				{Received: true, ECN: ECNCE},
+			}},
+			{MediaSSRC: 2, MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNECT0},
+				{Received: true, ECN: ECNCE},
+			}},
+		},
+	}
+	assert.Equal(t, 3, report.CEMarkedCount())
+	assert.True(t, report.HasCEMarks())
+	assert.Zero(t, testing.AllocsPerRun(10, func() {
+		report.CEMarkedCount()
+		report.HasCEMarks()
+	}))
+
+	unmarked := CCFeedbackReport{
+		ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNECT1},
+				{Received: true},
+			}},
+		},
+	}
+	assert.Zero(t, unmarked.CEMarkedCount())
+	assert.False(t, unmarked.HasCEMarks())
+
+	t.Run("lost packets with CE bits", func(t *testing.T) {
+		data := []byte{
+			0x8B, 0xCD, 0x00, 0x05, // V=2, P=0, FMT=11, PT=205, Length=5
+			0x00, 0x00, 0x00, 0x01, // Sender SSRC=1
+			0x00, 0x00, 0x00, 0x02, // Media SSRC=2
+			0x00, 0x0A, 0x00, 0x01, // begin_seq=10, num_reports=1
+			0x60, 0x00, 0x60, 0x00, // R=0 with ECN=CE, twice
+			0x00, 0x00, 0x00, 0x01, // Report Timestamp=1
+		}
+		var decoded CCFeedbackReport
+		assert.NoError(t, decoded.UnmarshalWithOptions(data, DecodeOptions{PreserveNotReceivedBits: true}))
+		assert.Equal(t, ECNCE, decoded.ReportBlocks[0].MetricBlocks[0].ECN)
+		assert.Zero(t, decoded.CEMarkedCount())
+		assert.False(t, decoded.HasCEMarks())
+	})
+}
+
+func TestCCFeedbackReportFirstCESequence(t *testing.T) {
+	ce := CCFeedbackMetricBlock{Received: true, ECN: ECNCE}
+	ect0 := CCFeedbackMetricBlock{Received: true, ECN: ECNECT0}
+	lostCE := CCFeedbackMetricBlock{ECN: ECNCE}
+
+	report := CCFeedbackReport{
+		ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 100, MetricBlocks: []CCFeedbackMetricBlock{ect0, lostCE, ect0, ce, ce}},
+			{MediaSSRC: 2, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{ce, ect0}},
+			// The block after the wrap comes first, but 65535 is earlier than 0.
+			{MediaSSRC: 3, BeginSequence: 0, MetricBlocks: []CCFeedbackMetricBlock{ce, ce}},
+			{MediaSSRC: 3, BeginSequence: 65534, MetricBlocks: []CCFeedbackMetricBlock{ect0, ce}},
+			{MediaSSRC: 4, BeginSequence: 50, MetricBlocks: []CCFeedbackMetricBlock{ect0, lostCE}},
+		},
+	}
+
+	for _, test := range []struct {
+		Name  string
+		SSRC  uint32
+		Seq   uint16
+		Found bool
+	}{
+		{Name: "mid-range", SSRC: 1, Seq: 103, Found: true},
+		{Name: "first metric block", SSRC: 2, Seq: 10, Found: true},
+		{Name: "wrap", SSRC: 3, Seq: 65535, Found: true},
+		{Name: "only lost", SSRC: 4},
+		{Name: "unknown SSRC", SSRC: 5},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			seq, found := report.FirstCESequence(test.SSRC)
+			assert.Equal(t, test.Found, found)
+			assert.Equal(t, test.Seq, seq)
+		})
+	}
+}
+
+func TestCCFeedbackReportReset(t *testing.T) {
+	report := newBenchmarkReport(16)
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+
+	var decoded CCFeedbackReport
+	assert.NoError(t, decoded.Unmarshal(data))
+	blocksCap := cap(decoded.ReportBlocks)
+	metricBlocksCap := cap(decoded.ReportBlocks[0].MetricBlocks)
+
+	decoded.Reset()
+	assert.Equal(t, CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{}}, decoded)
+	assert.Equal(t, blocksCap, cap(decoded.ReportBlocks))
+	assert.Equal(t, metricBlocksCap, cap(decoded.ReportBlocks[:1][0].MetricBlocks))
+
+	assert.Zero(t, testing.AllocsPerRun(10, func() {
+		decoded.Reset()
+		_ = decoded.Unmarshal(data)
+	}))
+	assert.Equal(t, report, decoded)
+
+	var empty CCFeedbackReport
+	empty.Reset()
+	assert.Equal(t, CCFeedbackReport{}, empty)
+}
+
+func TestUnmarshalCCFeedbackReports(t *testing.T) {
+	reports := []CCFeedbackReport{
+		{
+			SenderSSRC:      1,
+			ReportTimestamp: 2,
+			ReportBlocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 3, BeginSequence: 4, MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNCE, ArrivalTimeOffset: 5},
+					{},
+					{Received: true, ArrivalTimeOffset: 1},
+				}},
+			},
+		},
+		{
+			SenderSSRC:      1,
+			ReportTimestamp: 6,
+			ReportBlocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 3, BeginSequence: 7, MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ArrivalTimeOffset: 8},
+					{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 2},
+				}},
+				{MediaSSRC: 9, BeginSequence: 10, MetricBlocks: []CCFeedbackMetricBlock{{}, {}}},
+			},
+		},
+	}
+	var data []byte
+	for _, report := range reports {
+		b, err := report.Marshal()
+		assert.NoError(t, err)
+		data = append(data, b...)
+	}
+
+	decoded, err := UnmarshalCCFeedbackReports(data)
+	assert.NoError(t, err)
+	assert.Equal(t, reports, decoded)
+
+	_, err = UnmarshalCCFeedbackReports(nil)
+	assert.ErrorIs(t, err, errPacketTooShort)
+
+	_, err = UnmarshalCCFeedbackReports(data[:len(data)-1])
+	assert.ErrorIs(t, err, errLengthMismatch)
+
+	pli, err := (&PictureLossIndication{}).Marshal()
+	assert.NoError(t, err)
+	_, err = UnmarshalCCFeedbackReports(append(data, pli...))
+	assert.ErrorIs(t, err, errWrongType)
+}
+
+func TestCCFeedbackReportTruncateToMTU(t *testing.T) {
+	newReport := func() *CCFeedbackReport {
+		metricBlocks := func(n int) []CCFeedbackMetricBlock {
+			mbs := make([]CCFeedbackMetricBlock, n)
+			for i := range mbs {
+				mbs[i] = CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: uint16(100 + i)}
+			}
+			return mbs
+		}
+		return &CCFeedbackReport{
+			SenderSSRC:      1,
+			ReportTimestamp: 2,
+			ReportBlocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 3, BeginSequence: 0, MetricBlocks: metricBlocks(4)},
+				{MediaSSRC: 3, BeginSequence: 4, MetricBlocks: metricBlocks(4)},
+			},
+		}
+	}
+	assert.Equal(t, 44, newReport().MarshalSize())
+
+	for _, test := range []struct {
+		Name    string
+		MTU     int
+		Dropped int
+		Blocks  []CCFeedbackReportBlock
+	}{
+		{
+			Name:   "fits",
+			MTU:    44,
+			Blocks: newReport().ReportBlocks,
+		},
+		{
+			Name: "trims metric blocks",
+			MTU:  40,
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 3, BeginSequence: 2, MetricBlocks: newReport().ReportBlocks[0].MetricBlocks[2:]},
+				newReport().ReportBlocks[1],
+			},
+		},
+		{
+			Name:    "drops whole block",
+			MTU:     30,
+			Dropped: 1,
+			Blocks:  newReport().ReportBlocks[1:],
+		},
+		{
+			Name:    "drops and trims",
+			MTU:     24,
+			Dropped: 1,
+			Blocks: []CCFeedbackReportBlock{
+				{MediaSSRC: 3, BeginSequence: 6, MetricBlocks: newReport().ReportBlocks[1].MetricBlocks[2:]},
+			},
+		},
+		{
+			Name:    "too small",
+			MTU:     8,
+			Dropped: 2,
+			Blocks:  []CCFeedbackReportBlock{},
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			report := newReport()
+			assert.Equal(t, test.Dropped, report.TruncateToMTU(test.MTU))
+			assert.Equal(t, test.Blocks, report.ReportBlocks)
+			if test.MTU >= 12 {
+				assert.LessOrEqual(t, report.MarshalSize(), test.MTU)
+			}
+
+			// The truncated report is still valid on the wire.
+			data, err := report.Marshal()
+			assert.NoError(t, err)
+			var decoded CCFeedbackReport
+			assert.NoError(t, decoded.Unmarshal(data))
+			assert.Equal(t, report.ReportTimestamp, decoded.ReportTimestamp)
+			assert.Equal(t, len(report.ReportBlocks), len(decoded.ReportBlocks))
+		})
+	}
+
+	t.Run("media sources in turn", func(t *testing.T) {
+		block := func(ssrc uint32, begin uint16, n int) CCFeedbackReportBlock {
+			mbs := make([]CCFeedbackMetricBlock, n)
+			for i := range mbs {
+				mbs[i] = CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: uint16(begin) + uint16(i)}
+			}
+			return CCFeedbackReportBlock{MediaSSRC: ssrc, BeginSequence: begin, MetricBlocks: mbs}
+		}
+		report := CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{
+			block(1, 0, 10), block(1, 10, 10),
+			block(2, 100, 10), block(2, 110, 10),
+			block(3, 200, 10), block(3, 210, 10),
+		}}
+		assert.Equal(t, 180, report.MarshalSize())
+
+		// Eight turns of four bytes: three for the first two media sources
+		// and two for the last one.
+		assert.Equal(t, 0, report.TruncateToMTU(150))
+		assert.Equal(t, 148, report.MarshalSize())
+		assert.Equal(t, []CCFeedbackReportBlock{
+			block(1, 6, 4), block(1, 10, 10),
+			block(2, 106, 4), block(2, 110, 10),
+			block(3, 204, 6), block(3, 210, 10),
+		}, report.ReportBlocks)
+
+		// The oldest blocks go before any newer block is touched.
+		assert.Equal(t, 2, report.TruncateToMTU(112))
+		assert.Equal(t, 112, report.MarshalSize())
+		assert.Equal(t, []CCFeedbackReportBlock{
+			block(1, 10, 10),
+			block(2, 110, 10),
+			block(3, 206, 4), block(3, 210, 10),
+		}, report.ReportBlocks)
+	})
+}
+
+func TestCCFeedbackReportMarshalByteOrder(t *testing.T) {
+	report := CCFeedbackReport{
+		SenderSSRC:      0x01020304,
+		ReportTimestamp: 0x0B0C0D0E,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{
+				MediaSSRC:     0x05060708,
+				BeginSequence: 0x090A,
+				MetricBlocks: []CCFeedbackMetricBlock{
+					{Received: true, ECN: ECNCE, ArrivalTimeOffset: 0x0123},
+					{Received: false},
+				},
+			},
+		},
+	}
+	data, err := report.Marshal()
+	assert.NoError(t, err)
+	assert.Len(t, data, 24)
+
+	// Every multi-byte field is written most significant byte first.
+	for _, field := range []struct {
+		Name   string
+		Offset int
+		Want   []byte
+	}{
+		{Name: "length", Offset: 2, Want: []byte{0x00, 0x05}},
+		{Name: "sender SSRC", Offset: 4, Want: []byte{0x01, 0x02, 0x03, 0x04}},
+		{Name: "media SSRC", Offset: 8, Want: []byte{0x05, 0x06, 0x07, 0x08}},
+		{Name: "begin_seq", Offset: 12, Want: []byte{0x09, 0x0A}},
+		{Name: "num_reports", Offset: 14, Want: []byte{0x00, 0x01}},
+		// R=1, ECN=11 and the 13-bit offset 0x0123
+		{Name: "metric block", Offset: 16, Want: []byte{0xE1, 0x23}},
+		{Name: "report timestamp", Offset: 20, Want: []byte{0x0B, 0x0C, 0x0D, 0x0E}},
+	} {
+		assert.Equal(t, field.Want, data[field.Offset:field.Offset+len(field.Want)], field.Name)
+	}
+}
+
+func TestCCFeedbackReportSetTimestampFromArrivals(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 500000000, time.UTC)
+	arrivals := map[uint32][]PacketArrival{
+		2: {
+			{SequenceNumber: 10, Received: true, ECN: ECNECT0, Arrival: now.Add(-time.Second)},
+			{SequenceNumber: 12, Received: true, ECN: ECNCE, Arrival: now},
+		},
+		1: {
+			{SequenceNumber: 100, Received: true, Arrival: now.Add(-500 * time.Millisecond)},
+			{SequenceNumber: 101, Received: true, Arrival: now.Add(-250 * time.Millisecond)},
+		},
+	}
+
+	report := CCFeedbackReport{SenderSSRC: 7}
+	assert.NoError(t, report.SetTimestampFromArrivals(now, arrivals))
+	assert.Equal(t, uint32(ToNTPTime(now)>>16), report.ReportTimestamp)
+	assert.Equal(t, []CCFeedbackReportBlock{
+		{
+			MediaSSRC:     1,
+			BeginSequence: 100,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ArrivalTimeOffset: 512},
+				{Received: true, ArrivalTimeOffset: 256},
+			},
+		},
+		{
+			MediaSSRC:     2,
+			BeginSequence: 10,
+			MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNECT0, ArrivalTimeOffset: 1024},
+				{},
+				// The most recent arrival is at the report timestamp.
+				{Received: true, ECN: ECNCE, ArrivalTimeOffset: 0},
+			},
+		},
+	}, report.ReportBlocks)
+	assert.Equal(t, uint32(7), report.SenderSSRC)
+
+	t.Run("single arrival", func(t *testing.T) {
+		report := CCFeedbackReport{SenderSSRC: 7, ReportTimestamp: 1}
+		err := report.SetTimestampFromArrivals(now, map[uint32][]PacketArrival{
+			2: arrivals[2],
+			3: {{SequenceNumber: 1, Received: true, Arrival: now}},
+		})
+		assert.ErrorIs(t, err, errSingleMetricBlock)
+		assert.Equal(t, CCFeedbackReport{SenderSSRC: 7, ReportTimestamp: 1}, report)
+	})
+
+	t.Run("across wrap", func(t *testing.T) {
+		var streamArrivals []PacketArrival
+		for i := 0; i < 20; i++ {
+			streamArrivals = append(streamArrivals, PacketArrival{
+				SequenceNumber: 65530 + uint16(i),
+				Received:       true,
+				Arrival:        now.Add(-time.Duration(20-i) * time.Second / 1024),
+			})
+		}
+		report := CCFeedbackReport{SenderSSRC: 7}
+		assert.NoError(t, report.SetTimestampFromArrivals(now, map[uint32][]PacketArrival{4: streamArrivals}))
+		assert.NoError(t, report.ValidateRFC8888())
+		if assert.Len(t, report.ReportBlocks, 2) {
+			assert.Equal(t, uint16(65530), report.ReportBlocks[0].BeginSequence)
+			assert.Len(t, report.ReportBlocks[0].MetricBlocks, 6)
+			assert.Equal(t, uint16(0), report.ReportBlocks[1].BeginSequence)
+			assert.Len(t, report.ReportBlocks[1].MetricBlocks, 14)
+			assert.Equal(t, CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: 1}, report.ReportBlocks[1].MetricBlocks[13])
+		}
+
+		data, err := report.Marshal()
+		assert.NoError(t, err)
+		var decoded CCFeedbackReport
+		assert.NoError(t, decoded.Unmarshal(data))
+		assert.Equal(t, report, decoded)
+	})
+}
+
+func TestNewCCFeedbackReportForStream(t *testing.T) {
+	metricBlocks := func(n int) []CCFeedbackMetricBlock {
+		out := make([]CCFeedbackMetricBlock, n)
+		for i := range out {
+			if i%5 != 0 {
+				out[i] = CCFeedbackMetricBlock{Received: true, ECN: ECNECT1, ArrivalTimeOffset: uint16(i % 8000)}
+			}
+		}
+		return out
+	}
+
+	for _, test := range []struct {
+		Name    string
+		Begin   uint16
+		Count   int
+		Sizes   []int
+		WantErr error
+	}{
+		{Name: "40000 packets", Begin: 100, Count: 40000, Sizes: []int{16384, 16384, 7232}},
+		{Name: "one more than a block", Begin: 0, Count: 16385, Sizes: []int{16383, 2}},
+		{Name: "wrap", Begin: 60000, Count: 40000, Sizes: []int{5536, 16384, 16384, 1696}},
+		{Name: "all sequence numbers", Begin: 0, Count: 65536, Sizes: []int{16384, 16384, 16384, 16384}},
+		{Name: "single packet before wrap", Begin: 65535, Count: 10, WantErr: errSingleMetricBlock},
+		{Name: "single packet after wrap", Begin: 65530, Count: 7, WantErr: errSingleMetricBlock},
+		{Name: "more than all sequence numbers", Begin: 0, Count: 65537, WantErr: errTooManyMetricBlocks},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			mbs := metricBlocks(test.Count)
+			report, err := NewCCFeedbackReportForStream(1, 2, test.Begin, mbs, 3)
+			if test.WantErr != nil {
+				assert.ErrorIs(t, err, test.WantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, report.ValidateRFC8888())
+
+			// The blocks continue one another and carry every metric block.
+			seq := test.Begin
+			var all []CCFeedbackMetricBlock
+			if assert.Len(t, report.ReportBlocks, len(test.Sizes)) {
+				for i, block := range report.ReportBlocks {
+					assert.Equal(t, uint32(2), block.MediaSSRC)
+					assert.Equal(t, seq, block.BeginSequence, "block %d", i)
+					assert.Len(t, block.MetricBlocks, test.Sizes[i], "block %d", i)
+					seq += uint16(len(block.MetricBlocks))
+					all = append(all, block.MetricBlocks...)
+				}
+			}
+			assert.Equal(t, mbs, all)
+
+			data, err := report.Marshal()
+			assert.NoError(t, err)
+			var decoded CCFeedbackReport
+			assert.NoError(t, decoded.Unmarshal(data))
+			assert.Equal(t, *report, decoded)
+		})
+	}
+}
+
+func TestNewLossReport(t *testing.T) {
+	rx := CCFeedbackMetricBlock{Received: true, ArrivalTimeOffset: 0x1FFF}
+
+	for _, test := range []struct {
+		Name     string
+		Begin    uint16
+		LostSeqs []uint16
+		Want     []CCFeedbackReportBlock
+		WantErr  error
+	}{
+		{
+			Name:     "burst",
+			Begin:    100,
+			LostSeqs: []uint16{104, 102, 103},
+			Want: []CCFeedbackReportBlock{
+				{MediaSSRC: 2, BeginSequence: 100, MetricBlocks: []CCFeedbackMetricBlock{rx, rx, {}, {}, {}}},
+			},
+		},
+		{
+			Name:     "wrap",
+			Begin:    65533,
+			LostSeqs: []uint16{65535, 1},
+			Want: []CCFeedbackReportBlock{
+				{MediaSSRC: 2, BeginSequence: 65533, MetricBlocks: []CCFeedbackMetricBlock{rx, rx, {}}},
+				{MediaSSRC: 2, BeginSequence: 0, MetricBlocks: []CCFeedbackMetricBlock{rx, {}}},
+			},
+		},
+		{
+			Name:    "no losses",
+			Begin:   1,
+			WantErr: errNoMetricBlocks,
+		},
+		{
+			Name:     "before begin",
+			Begin:    100,
+			LostSeqs: []uint16{99},
+			WantErr:  errLostBeforeBegin,
+		},
+		{
+			Name:     "single metric block",
+			Begin:    100,
+			LostSeqs: []uint16{100},
+			WantErr:  errSingleMetricBlock,
+		},
+		{
+			Name:     "single metric block before wrap",
+			Begin:    65535,
+			LostSeqs: []uint16{2},
+			WantErr:  errSingleMetricBlock,
+		},
+		{
+			Name:     "single metric block after wrap",
+			Begin:    65534,
+			LostSeqs: []uint16{0},
+			WantErr:  errSingleMetricBlock,
+		},
+		{
+			Name:     "too long",
+			Begin:    0,
+			LostSeqs: []uint16{MaxMetricBlocksPerReport},
+			WantErr:  errTooManyReports,
+		},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			report, err := NewLossReport(1, 2, test.Begin, test.LostSeqs, 3)
+			if test.WantErr != nil {
+				assert.ErrorIs(t, err, test.WantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), report.SenderSSRC)
+			assert.Equal(t, uint32(3), report.ReportTimestamp)
+			assert.Equal(t, test.Want, report.ReportBlocks)
+			assert.NoError(t, report.ValidateRFC8888())
+
+			data, err := report.Marshal()
+			assert.NoError(t, err)
+			var decoded CCFeedbackReport
+			assert.NoError(t, decoded.Unmarshal(data))
+			assert.Equal(t, *report, decoded)
+		})
+	}
+
+	t.Run("single loss like single arrival", func(t *testing.T) {
+		// Neither constructor invents feedback on a neighbouring packet.
+		_, lossErr := NewLossReport(1, 2, 100, []uint16{100}, 3)
+		_, arrivalErr := NewCCFeedbackReportBlocks(2, time.Unix(10, 0), []PacketArrival{{SequenceNumber: 100}})
+		assert.ErrorIs(t, lossErr, errSingleMetricBlock)
+		assert.ErrorIs(t, arrivalErr, errSingleMetricBlock)
+		assert.EqualError(t, lossErr, arrivalErr.Error())
+	})
+}
+
+func TestNewCCFeedbackReportBlocksOmitAllLost(t *testing.T) {
+	reportTime := time.Unix(100, 0)
+	outage := []PacketArrival{
+		{SequenceNumber: 1},
+		{SequenceNumber: 2},
+		{SequenceNumber: 4},
+	}
+
+	t.Run("keep", func(t *testing.T) {
+		blocks, err := NewCCFeedbackReportBlocksWithOptions(1, reportTime, outage, BuildOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []CCFeedbackReportBlock{
+			{MediaSSRC: 1, BeginSequence: 1, MetricBlocks: make([]CCFeedbackMetricBlock, 4)},
+		}, blocks)
+	})
+
+	t.Run("omit", func(t *testing.T) {
+		blocks, err := NewCCFeedbackReportBlocksWithOptions(1, reportTime, outage, BuildOptions{OmitAllLost: true})
+		assert.NoError(t, err)
+		assert.Nil(t, blocks)
+	})
+
+	t.Run("omit split block", func(t *testing.T) {
+		// Only the second half of the split range is all lost.
+		blocks, err := NewCCFeedbackReportBlocksWithOptions(1, reportTime, []PacketArrival{
+			{SequenceNumber: 0, Received: true, Arrival: reportTime},
+			{SequenceNumber: MaxMetricBlocksPerReport + 1},
+		}, BuildOptions{OmitAllLost: true})
+		assert.NoError(t, err)
+		if assert.Len(t, blocks, 1) {
+			assert.Equal(t, uint16(0), blocks[0].BeginSequence)
+			assert.Len(t, blocks[0].MetricBlocks, MaxMetricBlocksPerReport)
+		}
+	})
+}
+
+func ExampleCCFeedbackMetricBlock_RawArrivalOffset() {
+	// Arrival time offsets count 1/1024 seconds, so 250ms is 256 units.
+	mb := CCFeedbackMetricBlock{
+		Received:          true,
+		ArrivalTimeOffset: uint16(250 * time.Millisecond * 1024 / time.Second),
+	}
+	fmt.Println(mb.RawArrivalOffset(), mb.ArrivalDelay())
+	// Output: 256 250ms
+}
+
+func TestCCFeedbackReportBlockLenMatchesMarshal(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 4, 15, 16, 1023, MaxMetricBlocksPerReport - 1, MaxMetricBlocksPerReport} {
+		block := CCFeedbackReportBlock{
+			MediaSSRC:    1,
+			MetricBlocks: make([]CCFeedbackMetricBlock, n),
+		}
+		data, err := block.marshal()
+		assert.NoError(t, err, "%d metric blocks", n)
+		assert.Equal(t, block.len(), len(data), "%d metric blocks", n)
+		// Odd counts are padded to an even number of metric blocks.
+		assert.Equal(t, 8+2*(n+n%2), len(data), "%d metric blocks", n)
+
+		report := CCFeedbackReport{ReportBlocks: []CCFeedbackReportBlock{block, block}}
+		data, err = report.Marshal()
+		assert.NoError(t, err, "%d metric blocks", n)
+		assert.Equal(t, report.MarshalSize(), len(data), "%d metric blocks", n)
+	}
+}
+
+func TestCCFeedbackReportSummary(t *testing.T) {
+	report := CCFeedbackReport{
+		SenderSSRC:      0x1234,
+		ReportTimestamp: 0xABCDEF01,
+		ReportBlocks: []CCFeedbackReportBlock{
+			{MediaSSRC: 1, MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNCE},
+				{},
+				{Received: true, ECN: ECNECT0},
+			}},
+			{MediaSSRC: 2, MetricBlocks: []CCFeedbackMetricBlock{{}, {}}},
+			// A second block for the same stream is not another stream.
+			{MediaSSRC: 1, BeginSequence: 3, MetricBlocks: []CCFeedbackMetricBlock{
+				{Received: true, ECN: ECNCE},
+				{Received: true, ECN: ECNECT1},
+			}},
+		},
+	}
+	assert.Equal(t, "CCFB sender=0x00001234 ts=0xabcdef01 streams=2 reported=7 lost=3 ce=2", report.Summary())
+	assert.Equal(t, "CCFB sender=0x00000000 ts=0x00000000 streams=0 reported=0 lost=0 ce=0", (&CCFeedbackReport{}).Summary())
 }
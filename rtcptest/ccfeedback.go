@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcptest
+
+import (
+	"math/rand"
+
+	"github.com/pion/rtcp"
+)
+
+// SynthesizeCCFeedback returns a congestion control feedback report about
+// count packets of the media source ssrc, starting at sequence number begin.
+// Every packet is independently reported lost with probability lossRate, and
+// every received packet is marked CE with probability ceRate and ECT(0)
+// otherwise. Packets arrive 1/1024 seconds apart and the last one arrives at
+// the report timestamp; earlier packets that are out of range are reported
+// as over-range.
+//
+// The packets are spread over report blocks by
+// rtcp.NewCCFeedbackReportForStream, so the report is valid and marshals
+// successfully. Its error is returned for counts it can not report, such as
+// a single packet or a wraparound that leaves one on either side.
+//
+// All randomness is drawn from rng, so the report is deterministic given a
+// seeded rng.
+func SynthesizeCCFeedback(ssrc uint32, begin uint16, count int, lossRate, ceRate float64, rng *rand.Rand) (*rtcp.CCFeedbackReport, error) {
+	metrics := make([]rtcp.CCFeedbackMetricBlock, count)
+	for i := range metrics {
+		if rng.Float64() < lossRate {
+			continue
+		}
+		ecn := rtcp.ECNECT0
+		if rng.Float64() < ceRate {
+			ecn = rtcp.ECNCE
+		}
+		offset := count - 1 - i
+		if offset > rtcp.ArrivalTimeOffsetOverRange {
+			offset = rtcp.ArrivalTimeOffsetOverRange
+		}
+		metrics[i] = rtcp.CCFeedbackMetricBlock{
+			Received:          true,
+			ECN:               ecn,
+			ArrivalTimeOffset: uint16(offset),
+		}
+	}
+
+	return rtcp.NewCCFeedbackReportForStream(0, ssrc, begin, metrics, 0)
+}
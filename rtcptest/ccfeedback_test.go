@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcptest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynthesizeCCFeedback(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Begin  uint16
+		Count  int
+		Blocks []int
+	}{
+		{Name: "single block", Begin: 100, Count: 1000, Blocks: []int{1000}},
+		{Name: "split", Begin: 0, Count: rtcp.MaxMetricBlocksPerReport + 1, Blocks: []int{rtcp.MaxMetricBlocksPerReport - 1, 2}},
+		{Name: "wraparound", Begin: 0xFFF0, Count: 32, Blocks: []int{16, 16}},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			report, err := SynthesizeCCFeedback(1, test.Begin, test.Count, 0.1, 0.2, rand.New(rand.NewSource(1))) //nolint:gosec
+			assert.NoError(t, err)
+			assert.NoError(t, report.ValidateRFC8888())
+			AssertRoundTrip(t, report)
+
+			seq := test.Begin
+			assert.Len(t, report.ReportBlocks, len(test.Blocks))
+			for i, block := range report.ReportBlocks {
+				assert.NoError(t, block.Validate())
+				assert.Equal(t, seq, block.BeginSequence)
+				assert.Len(t, block.MetricBlocks, test.Blocks[i])
+				seq += uint16(len(block.MetricBlocks))
+			}
+		})
+	}
+
+	t.Run("rates", func(t *testing.T) {
+		report, err := SynthesizeCCFeedback(1, 0, 10000, 0.1, 0.2, rand.New(rand.NewSource(1))) //nolint:gosec
+		assert.NoError(t, err)
+		ect0, ect1, ce, nonECT, lost := report.ECNCounts()
+		assert.InDelta(t, 1000, lost, 100)
+		assert.InDelta(t, 1800, ce, 150)
+		assert.Equal(t, 10000-lost-ce, ect0)
+		assert.Zero(t, ect1)
+		assert.Zero(t, nonECT)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		a, err := SynthesizeCCFeedback(1, 0, 100, 0.5, 0.5, rand.New(rand.NewSource(7))) //nolint:gosec
+		assert.NoError(t, err)
+		b, err := SynthesizeCCFeedback(1, 0, 100, 0.5, 0.5, rand.New(rand.NewSource(7))) //nolint:gosec
+		assert.NoError(t, err)
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("single metric block", func(t *testing.T) {
+		for _, count := range []int{1, 3} {
+			_, err := SynthesizeCCFeedback(1, 0xFFFF, count, 0, 0, rand.New(rand.NewSource(1))) //nolint:gosec
+			assert.Error(t, err, "count %d", count)
+		}
+	})
+}
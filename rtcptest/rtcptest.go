@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package rtcptest provides helpers for testing code built on package rtcp.
+package rtcptest
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/pion/rtcp"
+)
+
+// AssertRoundTrip marshals p, unmarshals the result into a fresh value of the
+// same concrete type, marshals that value again and reports an error on t if
+// the two encodings differ. Padding has to survive the round trip as well,
+// since the encodings are compared byte for byte.
+func AssertRoundTrip(t testing.TB, p rtcp.Packet) {
+	t.Helper()
+
+	want, err := p.Marshal()
+	if err != nil {
+		t.Errorf("Marshal %T: %v", p, err)
+		return
+	}
+
+	decoded := newPacket(p)
+	if err := decoded.Unmarshal(want); err != nil {
+		t.Errorf("Unmarshal %T: %v", p, err)
+		return
+	}
+
+	got, err := decoded.Marshal()
+	if err != nil {
+		t.Errorf("Marshal decoded %T: %v", p, err)
+		return
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("%T round trip: got %x, want %x", p, got, want)
+	}
+}
+
+// newPacket returns a pointer to a zero value of the type p points to.
+// Unmarshal always has a pointer receiver, so every Packet is a pointer.
+func newPacket(p rtcp.Packet) rtcp.Packet {
+	decoded, _ := reflect.New(reflect.TypeOf(p).Elem()).Interface().(rtcp.Packet)
+	return decoded
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcptest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder captures errors reported by AssertRoundTrip
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// truncatedPacket drops its last byte when re-marshaled after Unmarshal
+type truncatedPacket struct {
+	rtcp.RawPacket
+}
+
+func (p *truncatedPacket) Unmarshal(rawPacket []byte) error {
+	return p.RawPacket.Unmarshal(rawPacket[:len(rawPacket)-1])
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	for _, p := range []rtcp.Packet{
+		&rtcp.CCFeedbackReport{
+			SenderSSRC: 1,
+			ReportBlocks: []rtcp.CCFeedbackReportBlock{
+				{
+					MediaSSRC:     2,
+					BeginSequence: 3,
+					MetricBlocks: []rtcp.CCFeedbackMetricBlock{
+						{Received: true, ECN: rtcp.ECNCE, ArrivalTimeOffset: 4},
+						{Received: false},
+						{Received: true, ArrivalTimeOffset: 5},
+					},
+				},
+			},
+			ReportTimestamp: 6,
+		},
+		&rtcp.CompoundPacket{
+			&rtcp.ReceiverReport{SSRC: 1, ProfileExtensions: []byte{}},
+			rtcp.NewCNAMESourceDescription(1, "cname"),
+			&rtcp.CCFeedbackReport{SenderSSRC: 1, ReportTimestamp: 2},
+		},
+		&rtcp.PictureLossIndication{SenderSSRC: 1, MediaSSRC: 2},
+		&rtcp.SenderReport{SSRC: 1, NTPTime: 2, RTPTime: 3, Reports: []rtcp.ReceptionReport{{SSRC: 4}}},
+		rtcp.NewCNAMESourceDescription(1, "cname"),
+		// v=2, p=1, count=0, APP, len=2, with 3 octets of padding
+		&rtcp.RawPacket{0xa0, 0xcc, 0x00, 0x02, 0x01, 0x02, 0x03, 0x04, 0x05, 0x00, 0x00, 0x03},
+	} {
+		p := p
+		t.Run(fmt.Sprintf("%T", p), func(t *testing.T) {
+			AssertRoundTrip(t, p)
+		})
+	}
+
+	t.Run("Mismatch", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertRoundTrip(r, &truncatedPacket{rtcp.RawPacket{0x80, 0xcc, 0x00, 0x01, 0x01, 0x02, 0x03, 0x04}})
+		assert.Len(t, r.errors, 1)
+	})
+
+	t.Run("MarshalError", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertRoundTrip(r, &rtcp.Goodbye{Sources: make([]uint32, 32)})
+		assert.Len(t, r.errors, 1)
+	})
+}
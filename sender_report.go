@@ -6,6 +6,7 @@ package rtcp
 import (
 	"encoding/binary"
 	"fmt"
+	"time"
 )
 
 // A SenderReport (SR) packet provides reception quality feedback for an RTP stream
@@ -228,6 +229,11 @@ func (r *SenderReport) DestinationSSRC() []uint32 {
 	return out
 }
 
+// Kind returns KindSenderReport.
+func (r *SenderReport) Kind() PacketKind {
+	return KindSenderReport
+}
+
 // MarshalSize returns the size of the packet once marshaled
 func (r *SenderReport) MarshalSize() int {
 	repsLength := 0
@@ -260,3 +266,29 @@ func (r SenderReport) String() string {
 	out += fmt.Sprintf("\tProfile Extension Data: %v\n", r.ProfileExtensions)
 	return out
 }
+
+// ntpEpochOffset is the number of seconds between the NTP epoch
+// (1900-01-01) and the Unix epoch (1970-01-01).
+const ntpEpochOffset = 2208988800
+
+// ToNTPTime converts t to a 64-bit NTP timestamp: seconds since 1900 in the
+// upper 32 bits and the fraction of a second in the lower 32 bits. Times
+// outside of NTP era 0 (1900 to 2036) wrap around.
+func ToNTPTime(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := (uint64(t.Nanosecond())<<32 + uint64(time.Second)/2) / uint64(time.Second)
+	return seconds<<32 + fraction
+}
+
+// FromNTPTime converts a 64-bit NTP timestamp, as carried in NTPTime, to a
+// time.Time, assuming NTP era 0.
+func FromNTPTime(ntp uint64) time.Time {
+	seconds := int64(ntp>>32) - ntpEpochOffset
+	nanoseconds := ((ntp&0xFFFFFFFF)*uint64(time.Second) + 1<<31) >> 32
+	return time.Unix(seconds, int64(nanoseconds))
+}
+
+// Time returns the wallclock time of the report, converted from NTPTime.
+func (r SenderReport) Time() time.Time {
+	return FromNTPTime(r.NTPTime)
+}
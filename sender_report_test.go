@@ -7,6 +7,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var _ Packet = (*SenderReport)(nil) // assert is a Packet
@@ -295,3 +296,46 @@ func TestSenderReportRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestNTPTime(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Time time.Time
+		NTP  uint64
+	}{
+		{
+			Name: "unix epoch",
+			Time: time.Unix(0, 0),
+			NTP:  0x83AA7E80_00000000,
+		},
+		{
+			Name: "half second",
+			Time: time.Unix(0, 500_000_000),
+			NTP:  0x83AA7E80_80000000,
+		},
+		{
+			Name: "2023",
+			Time: time.Date(2023, time.January, 1, 0, 0, 0, 250_000_000, time.UTC),
+			NTP:  0xE75B4B80_40000000,
+		},
+	} {
+		if got := ToNTPTime(test.Time); got != test.NTP {
+			t.Errorf("ToNTPTime(%s) = %#x, want %#x", test.Name, got, test.NTP)
+		}
+		if got := FromNTPTime(test.NTP); !got.Equal(test.Time) {
+			t.Errorf("FromNTPTime(%s) = %v, want %v", test.Name, got, test.Time)
+		}
+	}
+
+	// The 32-bit fraction has a resolution of about 233ps, so converting
+	// back and forth must not lose more than a nanosecond.
+	now := time.Date(2024, time.March, 3, 12, 34, 56, 123_456_789, time.UTC)
+	if got := FromNTPTime(ToNTPTime(now)); got.Sub(now).Abs() > time.Nanosecond {
+		t.Errorf("FromNTPTime(ToNTPTime(%v)) = %v", now, got)
+	}
+
+	sr := SenderReport{NTPTime: ToNTPTime(now)}
+	if got := sr.Time(); got.Sub(now).Abs() > time.Nanosecond {
+		t.Errorf("SenderReport.Time() = %v, want %v", got, now)
+	}
+}
@@ -45,12 +45,14 @@ func (p SliceLossIndication) Marshal() ([]byte, error) {
 	}
 
 	rawPacket := make([]byte, sliOffset+(len(p.SLI)*4))
-	binary.BigEndian.PutUint32(rawPacket, p.SenderSSRC)
-	binary.BigEndian.PutUint32(rawPacket[4:], p.MediaSSRC)
+	if _, err := p.FeedbackHeader().MarshalTo(rawPacket); err != nil {
+		return nil, err
+	}
 	for i, s := range p.SLI {
-		sli := ((uint32(s.First) & 0x1FFF) << 19) |
-			((uint32(s.Number) & 0x1FFF) << 6) |
-			(uint32(s.Picture) & 0x3F)
+		sli, err := s.pack()
+		if err != nil {
+			return nil, fmt.Errorf("SLI entry %d: %w", i, err)
+		}
 		binary.BigEndian.PutUint32(rawPacket[sliOffset+(4*i):], sli)
 	}
 	hData, err := p.Header().Marshal()
@@ -80,19 +82,62 @@ func (p *SliceLossIndication) Unmarshal(rawPacket []byte) error {
 		return errWrongType
 	}
 
-	p.SenderSSRC = binary.BigEndian.Uint32(rawPacket[headerLength:])
-	p.MediaSSRC = binary.BigEndian.Uint32(rawPacket[headerLength+ssrcLength:])
+	var fh FeedbackHeader
+	if err := fh.Unmarshal(rawPacket[headerLength:]); err != nil {
+		return err
+	}
+	p.SenderSSRC, p.MediaSSRC = fh.SenderSSRC, fh.MediaSSRC
 	for i := headerLength + sliOffset; i < (headerLength + int(h.Length*4)); i += 4 {
-		sli := binary.BigEndian.Uint32(rawPacket[i:])
-		p.SLI = append(p.SLI, SLIEntry{
-			First:   uint16((sli >> 19) & 0x1FFF),
-			Number:  uint16((sli >> 6) & 0x1FFF),
-			Picture: uint8(sli & 0x3F),
-		})
+		var entry SLIEntry
+		if err := entry.unpack(binary.BigEndian.Uint32(rawPacket[i:])); err != nil {
+			return err
+		}
+		p.SLI = append(p.SLI, entry)
 	}
 	return nil
 }
 
+const (
+	sliFirstBits   = 13
+	sliNumberBits  = 13
+	sliPictureBits = 6
+)
+
+// pack encodes the entry into its 32-bit wire format, failing if a field
+// does not fit into its bits.
+func (s SLIEntry) pack() (uint32, error) {
+	sli, err := setNBitsOfUint32(0, sliFirstBits, 0, uint32(s.First))
+	if err != nil {
+		return 0, fmt.Errorf("first: %w", err)
+	}
+	sli, err = setNBitsOfUint32(sli, sliNumberBits, sliFirstBits, uint32(s.Number))
+	if err != nil {
+		return 0, fmt.Errorf("number: %w", err)
+	}
+	sli, err = setNBitsOfUint32(sli, sliPictureBits, sliFirstBits+sliNumberBits, uint32(s.Picture))
+	if err != nil {
+		return 0, fmt.Errorf("picture: %w", err)
+	}
+	return sli, nil
+}
+
+func (s *SLIEntry) unpack(sli uint32) error {
+	first, err := getNBitsFromUint32(sli, sliFirstBits, 0)
+	if err != nil {
+		return err
+	}
+	number, err := getNBitsFromUint32(sli, sliNumberBits, sliFirstBits)
+	if err != nil {
+		return err
+	}
+	picture, err := getNBitsFromUint32(sli, sliPictureBits, sliFirstBits+sliNumberBits)
+	if err != nil {
+		return err
+	}
+	*s = SLIEntry{First: uint16(first), Number: uint16(number), Picture: uint8(picture)}
+	return nil
+}
+
 // MarshalSize returns the size of the packet once marshaled
 func (p *SliceLossIndication) MarshalSize() int {
 	return headerLength + sliOffset + (len(p.SLI) * 4)
@@ -111,7 +156,17 @@ func (p *SliceLossIndication) String() string {
 	return fmt.Sprintf("SliceLossIndication %x %x %+v", p.SenderSSRC, p.MediaSSRC, p.SLI)
 }
 
+// FeedbackHeader returns the SSRCs of the sender and the media source
+func (p SliceLossIndication) FeedbackHeader() FeedbackHeader {
+	return FeedbackHeader{SenderSSRC: p.SenderSSRC, MediaSSRC: p.MediaSSRC}
+}
+
 // DestinationSSRC returns an array of SSRC values that this packet refers to.
 func (p *SliceLossIndication) DestinationSSRC() []uint32 {
 	return []uint32{p.MediaSSRC}
 }
+
+// Kind returns KindSliceLossIndication.
+func (p *SliceLossIndication) Kind() PacketKind {
+	return KindSliceLossIndication
+}
@@ -103,6 +103,35 @@ func TestSliceLossIndicationRoundTrip(t *testing.T) {
 				SLI:        []SLIEntry{{1, 0xAA, 0x1F}, {1034, 0x05, 0x6}},
 			},
 		},
+		{
+			Name: "field maximums",
+			Report: SliceLossIndication{
+				SenderSSRC: 0x902f9e2e,
+				MediaSSRC:  0x902f9e2e,
+				SLI:        []SLIEntry{{0x1FFF, 0, 0}, {0, 0x1FFF, 0}, {0, 0, 0x3F}, {0x1FFF, 0x1FFF, 0x3F}},
+			},
+		},
+		{
+			Name: "first overflow",
+			Report: SliceLossIndication{
+				SLI: []SLIEntry{{0x2000, 0, 0}},
+			},
+			WantError: errValueTooLarge,
+		},
+		{
+			Name: "number overflow",
+			Report: SliceLossIndication{
+				SLI: []SLIEntry{{0, 0x2000, 0}},
+			},
+			WantError: errValueTooLarge,
+		},
+		{
+			Name: "picture overflow",
+			Report: SliceLossIndication{
+				SLI: []SLIEntry{{0, 0, 0x40}},
+			},
+			WantError: errValueTooLarge,
+		},
 	} {
 		data, err := test.Report.Marshal()
 		if got, want := err, test.WantError; !errors.Is(got, want) {
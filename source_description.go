@@ -359,6 +359,11 @@ func (s *SourceDescription) DestinationSSRC() []uint32 {
 	return out
 }
 
+// Kind returns KindSourceDescription.
+func (s *SourceDescription) Kind() PacketKind {
+	return KindSourceDescription
+}
+
 func (s *SourceDescription) String() string {
 	out := "Source Description:\n"
 	for _, c := range s.Chunks {
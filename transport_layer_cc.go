@@ -558,6 +558,11 @@ func (t TransportLayerCC) DestinationSSRC() []uint32 {
 	return []uint32{t.MediaSSRC}
 }
 
+// Kind returns KindTransportLayerCC.
+func (t TransportLayerCC) Kind() PacketKind {
+	return KindTransportLayerCC
+}
+
 func min(x, y uint16) uint16 {
 	if x < y {
 		return x
@@ -101,8 +101,9 @@ func (p TransportLayerNack) Marshal() ([]byte, error) {
 	}
 
 	rawPacket := make([]byte, nackOffset+(len(p.Nacks)*4))
-	binary.BigEndian.PutUint32(rawPacket, p.SenderSSRC)
-	binary.BigEndian.PutUint32(rawPacket[4:], p.MediaSSRC)
+	if _, err := p.FeedbackHeader().MarshalTo(rawPacket); err != nil {
+		return nil, err
+	}
 	for i := 0; i < len(p.Nacks); i++ {
 		binary.BigEndian.PutUint16(rawPacket[nackOffset+(4*i):], p.Nacks[i].PacketID)
 		binary.BigEndian.PutUint16(rawPacket[nackOffset+(4*i)+2:], uint16(p.Nacks[i].LostPackets))
@@ -140,8 +141,11 @@ func (p *TransportLayerNack) Unmarshal(rawPacket []byte) error {
 		return errBadLength
 	}
 
-	p.SenderSSRC = binary.BigEndian.Uint32(rawPacket[headerLength:])
-	p.MediaSSRC = binary.BigEndian.Uint32(rawPacket[headerLength+ssrcLength:])
+	var fh FeedbackHeader
+	if err := fh.Unmarshal(rawPacket[headerLength:]); err != nil {
+		return err
+	}
+	p.SenderSSRC, p.MediaSSRC = fh.SenderSSRC, fh.MediaSSRC
 	for i := headerLength + nackOffset; i < (headerLength + int(h.Length*4)); i += 4 {
 		p.Nacks = append(p.Nacks, NackPair{
 			binary.BigEndian.Uint16(rawPacket[i:]),
@@ -175,7 +179,17 @@ func (p TransportLayerNack) String() string {
 	return out
 }
 
+// FeedbackHeader returns the SSRCs of the sender and the media source
+func (p TransportLayerNack) FeedbackHeader() FeedbackHeader {
+	return FeedbackHeader{SenderSSRC: p.SenderSSRC, MediaSSRC: p.MediaSSRC}
+}
+
 // DestinationSSRC returns an array of SSRC values that this packet refers to.
 func (p *TransportLayerNack) DestinationSSRC() []uint32 {
 	return []uint32{p.MediaSSRC}
 }
+
+// Kind returns KindTransportLayerNack.
+func (p *TransportLayerNack) Kind() PacketKind {
+	return KindTransportLayerNack
+}
@@ -206,10 +206,45 @@ func TestTransportLayerNackPairGeneration(t *testing.T) {
 				{PacketID: 500, LostPackets: 0x3},
 			},
 		},
+		{
+			"Run spanning multiple NACKPairs",
+			sequenceRange(100, 140),
+			[]NackPair{
+				{PacketID: 100, LostPackets: 0xFFFF},
+				{PacketID: 117, LostPackets: 0xFFFF},
+				{PacketID: 134, LostPackets: 0x003F},
+			},
+		},
+		{
+			"Run across sequence number wrap",
+			sequenceRange(65530, 65545),
+			[]NackPair{
+				{PacketID: 65530, LostPackets: 0x7FFF},
+			},
+		},
 	} {
 		actual := NackPairsFromSequenceNumbers(test.SequenceNumbers)
 		if !reflect.DeepEqual(actual, test.Expected) {
 			t.Fatalf("%q NackPair generation mismatch: got %#v, want %#v", test.Name, actual, test.Expected)
 		}
+
+		// Expanding the pairs again must yield the original sequence numbers.
+		expanded := []uint16{}
+		for i := range actual {
+			expanded = append(expanded, actual[i].PacketList()...)
+		}
+		if !reflect.DeepEqual(expanded, test.SequenceNumbers) {
+			t.Fatalf("%q PacketList mismatch: got %v, want %v", test.Name, expanded, test.SequenceNumbers)
+		}
+	}
+}
+
+// sequenceRange returns the sequence numbers from first to last inclusive,
+// wrapping around at 65535.
+func sequenceRange(first, last int) []uint16 {
+	out := []uint16{}
+	for i := first; i <= last; i++ {
+		out = append(out, uint16(i))
 	}
+	return out
 }
@@ -23,6 +23,40 @@ func setNBitsOfUint16(src, size, startIndex, val uint16) (uint16, error) {
 	return src | (val << (16 - size - startIndex)), nil
 }
 
+// getNBitsFromUint16 returns the size bits of src that start at startIndex,
+// counting from the most significant bit.
+func getNBitsFromUint16(src, size, startIndex uint16) (uint16, error) {
+	if startIndex+size > 16 {
+		return 0, errInvalidSizeOrStartIndex
+	}
+
+	return (src >> (16 - size - startIndex)) & (1<<size - 1), nil
+}
+
+// setNBitsOfUint32 will left-shift val to startIndex position and set it in
+// src. Unlike setNBitsOfUint16 it does not truncate val, but returns
+// errValueTooLarge if it does not fit into size bits.
+func setNBitsOfUint32(src, size, startIndex, val uint32) (uint32, error) {
+	if startIndex+size > 32 {
+		return 0, errInvalidSizeOrStartIndex
+	}
+	if uint64(val) >= 1<<size {
+		return 0, errValueTooLarge
+	}
+
+	return src | (val << (32 - size - startIndex)), nil
+}
+
+// getNBitsFromUint32 returns the size bits of src that start at startIndex,
+// counting from the most significant bit.
+func getNBitsFromUint32(src, size, startIndex uint32) (uint32, error) {
+	if startIndex+size > 32 {
+		return 0, errInvalidSizeOrStartIndex
+	}
+
+	return uint32((uint64(src) >> (32 - size - startIndex)) & (1<<size - 1)), nil
+}
+
 // appendBit32 will left-shift and append n bits of val
 func appendNBitsToUint32(src, n, val uint32) uint32 {
 	return (src << n) | (val & (0xFFFFFFFF >> (32 - n)))
@@ -74,3 +74,78 @@ func TestSetNBitsOfUint16(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNBitsFromUint16(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		source uint16
+		size   uint16
+		index  uint16
+		result uint16
+		err    error
+	}{
+		{"getFirstBit", 0x8000, 1, 0, 1, nil},
+		{"getECN", 0b1010_0000_0000_0000, 2, 1, 0b01, nil},
+		{"getLastThirteenBits", 0xFFFF, 13, 3, 0x1FFF, nil},
+		{"getWholeWord", 0xABCD, 16, 0, 0xABCD, nil},
+		{"getLastBit", 0x0001, 1, 15, 1, nil},
+		{"outOfBounds", 0xFFFF, 2, 15, 0, errInvalidSizeOrStartIndex},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := getNBitsFromUint16(test.source, test.size, test.index)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.result, got)
+		})
+	}
+}
+
+func TestSetNBitsOfUint32(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		source uint32
+		size   uint32
+		index  uint32
+		value  uint32
+		result uint32
+		err    error
+	}{
+		{"setFirstByte", 0, 8, 0, 0xAB, 0xAB000000, nil},
+		{"setLast24Bits", 0xAB000000, 24, 8, 0xFFFFFF, 0xABFFFFFF, nil},
+		{"setWholeWord", 0, 32, 0, 0xDEADBEEF, 0xDEADBEEF, nil},
+		{"setLastBit", 0, 1, 31, 1, 1, nil},
+		{"valueTooLarge", 0, 24, 8, 1 << 24, 0, errValueTooLarge},
+		{"outOfBounds", 0, 8, 25, 1, 0, errInvalidSizeOrStartIndex},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := setNBitsOfUint32(test.source, test.size, test.index, test.value)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.result, got)
+		})
+	}
+}
+
+func TestGetNBitsFromUint32(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		source uint32
+		size   uint32
+		index  uint32
+		result uint32
+		err    error
+	}{
+		{"getFirstByte", 0xAB123456, 8, 0, 0xAB, nil},
+		{"getLast24Bits", 0xAB123456, 24, 8, 0x123456, nil},
+		{"getWholeWord", 0xDEADBEEF, 32, 0, 0xDEADBEEF, nil},
+		{"getMiddleBits", 0x00FF0000, 4, 10, 0xF, nil},
+		{"outOfBounds", 0xFFFFFFFF, 8, 25, 0, errInvalidSizeOrStartIndex},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := getNBitsFromUint32(test.source, test.size, test.index)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.result, got)
+		})
+	}
+}